@@ -0,0 +1,177 @@
+package uruki
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// utm parameter keys of Campaign
+const (
+	UTMSource  = "utm_source"
+	UTMMedium  = "utm_medium"
+	UTMName    = "utm_campaign"
+	UTMTerm    = "utm_term"
+	UTMContent = "utm_content"
+
+	utmPrefix = "utm_"
+)
+
+// Campaign UTM campaign parameters of marketing link
+type Campaign struct {
+	// Source: utm_source, required
+	Source string
+	// Medium: utm_medium, required
+	Medium string
+	// Name: utm_campaign, required
+	Name string
+	// Term: utm_term
+	Term string
+	// Content: utm_content
+	Content string
+	// Custom: additional campaign parameters with full key, example map[string]string{"utm_id": "ramadhan-01"}
+	Custom map[string]string
+}
+
+// CampaignOptions options for SetCampaign
+type CampaignOptions struct {
+	// AllowPartial: skip validation of required source, medium and name
+	AllowPartial bool
+	// Lowercase: normalize campaign values into lower case
+	Lowercase bool
+	// SpaceReplacement: replace whitespace runs of campaign values, example "_" turn "Flash Sale" into "Flash_Sale"
+	SpaceReplacement string
+	// if you want using DefaultSpaceEncoding as SpaceEnc
+	UseDefaultEncode bool
+	// specify space encode if you use custom encoding, default PlusEncoding
+	SpaceEnc string
+}
+
+// SetCampaign set campaign parameters into query. existing utm parameters replaced in place, empty standard fields removed,
+// and new parameters appended in order source, medium, campaign, term, content then custom keys sorted
+func (ub *Builder) SetCampaign(campaign Campaign, opt CampaignOptions) error {
+	return ub.mutate("SetCampaign", []string{campaign.Source, campaign.Medium, campaign.Name, campaign.Term, campaign.Content}, func() error {
+		values := []queryPair{
+			{UTMSource, campaign.Source},
+			{UTMMedium, campaign.Medium},
+			{UTMName, campaign.Name},
+			{UTMTerm, campaign.Term},
+			{UTMContent, campaign.Content},
+		}
+		customKeys := make([]string, 0, len(campaign.Custom))
+		for k := range campaign.Custom {
+			customKeys = append(customKeys, k)
+		}
+		sort.Strings(customKeys)
+		for _, k := range customKeys {
+			key := strings.TrimSpace(k)
+			if err := checkQueryKey("SetCampaign", key); err != nil {
+				return err
+			}
+			values = append(values, queryPair{strings.ToLower(key), campaign.Custom[k]})
+		}
+
+		for i := range values {
+			values[i].val = normalizeCampaignValue(values[i].val, opt)
+		}
+		if !opt.AllowPartial {
+			for _, v := range values[:3] {
+				if len(v.val) < 1 {
+					return &Error{Op: "SetCampaign", Component: "query", Input: v.key, Err: ErrorCampaignRequired}
+				}
+			}
+		}
+
+		if opt.UseDefaultEncode {
+			opt.SpaceEnc = ub.defaultSpaceEncode
+		}
+		if len(opt.SpaceEnc) < 1 {
+			opt.SpaceEnc = PlusEncoding
+		}
+		pending := make(map[string]string, len(values))
+		for _, v := range values {
+			pending[v.key] = v.val
+		}
+
+		buildRawResult := make([]string, 0)
+		if len(ub.url.RawQuery) > 0 {
+			for _, queryParam := range strings.Split(ub.url.RawQuery, ampersandStr) {
+				q, _, _ := strings.Cut(queryParam, "=")
+				key, err := url.QueryUnescape(q)
+				if err != nil {
+					key = q
+				}
+				key = strings.ToLower(key)
+				val, ok := pending[key]
+				if !ok {
+					if hasCampaignKey(values, key) {
+						// duplicate of already replaced campaign key
+						continue
+					}
+					buildRawResult = append(buildRawResult, queryParam)
+					continue
+				}
+				delete(pending, key)
+				if len(val) > 0 {
+					buildRawResult = append(buildRawResult, q+"="+escapeQuery(val, opt.SpaceEnc))
+				}
+			}
+		}
+		for _, v := range values {
+			if val, ok := pending[v.key]; ok && len(val) > 0 {
+				buildRawResult = append(buildRawResult, escapeQuery(v.key, opt.SpaceEnc)+"="+escapeQuery(val, opt.SpaceEnc))
+			}
+		}
+		ub.url.RawQuery = strings.Join(buildRawResult, ampersandStr)
+		return nil
+	})
+}
+
+// GetCampaign get campaign parameters from query, utm parameters other than standard fields returned as Custom
+func (ub *Builder) GetCampaign() Campaign {
+	campaign := Campaign{Custom: map[string]string{}}
+	for key, values := range ub.url.Query() {
+		lowerKey := strings.ToLower(key)
+		if !strings.HasPrefix(lowerKey, utmPrefix) || len(values) < 1 {
+			continue
+		}
+		val := values[0]
+		switch lowerKey {
+		case UTMSource:
+			campaign.Source = val
+		case UTMMedium:
+			campaign.Medium = val
+		case UTMName:
+			campaign.Name = val
+		case UTMTerm:
+			campaign.Term = val
+		case UTMContent:
+			campaign.Content = val
+		default:
+			campaign.Custom[key] = val
+		}
+	}
+	return campaign
+}
+
+// normalizeCampaignValue trim and optionally lower case & replace whitespace of campaign value
+func normalizeCampaignValue(val string, opt CampaignOptions) string {
+	val = strings.TrimSpace(val)
+	if opt.Lowercase {
+		val = strings.ToLower(val)
+	}
+	if len(opt.SpaceReplacement) > 0 {
+		val = strings.Join(strings.Fields(val), opt.SpaceReplacement)
+	}
+	return val
+}
+
+// hasCampaignKey check key is one of campaign keys
+func hasCampaignKey(values []queryPair, key string) bool {
+	for _, v := range values {
+		if v.key == key {
+			return true
+		}
+	}
+	return false
+}
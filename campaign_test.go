@@ -0,0 +1,96 @@
+package uruki
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_SetCampaign(t *testing.T) {
+	type args struct {
+		name     string
+		url      string
+		campaign Campaign
+		opt      CampaignOptions
+		wantURL  string
+		wantErr  error
+	}
+
+	testCases := []args{
+		{
+			name:     "append campaign",
+			url:      "https://www.tokopedia.com/discovery/ramadhan?source=home",
+			campaign: Campaign{Source: "instagram", Medium: "social", Name: "ramadhan sale"},
+			wantURL:  "https://www.tokopedia.com/discovery/ramadhan?source=home&utm_source=instagram&utm_medium=social&utm_campaign=ramadhan+sale",
+		},
+		{
+			name:     "replace existing in place and remove stale term",
+			url:      "https://www.tokopedia.com/discovery?UTM_Source=fb&q=x&utm_term=old&utm_medium=cpc&utm_source=dup",
+			campaign: Campaign{Source: "tiktok", Medium: "video", Name: "flash"},
+			wantURL:  "https://www.tokopedia.com/discovery?UTM_Source=tiktok&q=x&utm_medium=video&utm_campaign=flash",
+		},
+		{
+			name:     "normalize casing and spaces with custom keys",
+			url:      "https://www.tokopedia.com/discovery",
+			campaign: Campaign{Source: " Instagram ", Medium: "Social", Name: "Flash  Sale 12.12", Content: "Banner A", Custom: map[string]string{"utm_id": "ID 1", "utm_creative_format": "Story"}},
+			opt:      CampaignOptions{Lowercase: true, SpaceReplacement: "_"},
+			wantURL:  "https://www.tokopedia.com/discovery?utm_source=instagram&utm_medium=social&utm_campaign=flash_sale_12.12&utm_content=banner_a&utm_creative_format=story&utm_id=id_1",
+		},
+		{
+			name:     "default space encoding",
+			url:      "https://www.tokopedia.com/discovery",
+			campaign: Campaign{Source: "ig", Medium: "social", Name: "flash sale"},
+			opt:      CampaignOptions{UseDefaultEncode: true},
+			wantURL:  "https://www.tokopedia.com/discovery?utm_source=ig&utm_medium=social&utm_campaign=flash%20sale",
+		},
+		{
+			name:     "missing required field",
+			url:      "https://www.tokopedia.com/discovery?utm_source=fb",
+			campaign: Campaign{Source: "ig", Name: "flash"},
+			wantURL:  "https://www.tokopedia.com/discovery?utm_source=fb",
+			wantErr:  ErrorCampaignRequired,
+		},
+		{
+			name:     "allow partial",
+			url:      "https://www.tokopedia.com/discovery?utm_source=fb&utm_medium=cpc",
+			campaign: Campaign{Source: "ig"},
+			opt:      CampaignOptions{AllowPartial: true},
+			wantURL:  "https://www.tokopedia.com/discovery?utm_source=ig",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ub, err := NewBuilder(Option{
+				URL:                tt.url,
+				DefaultSpaceEncode: PercentTwentyEncoding,
+			})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			err = ub.SetCampaign(tt.campaign, tt.opt)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("fail error test SetCampaign() got %v want %v", err, tt.wantErr)
+			}
+			if ub.GetURLResult() != tt.wantURL {
+				t.Errorf("fail value test SetCampaign() got %v want %v", ub.GetURLResult(), tt.wantURL)
+			}
+		})
+	}
+}
+
+func Test_GetCampaign(t *testing.T) {
+	ub, err := NewBuilder(Option{
+		URL: "https://www.tokopedia.com/discovery?utm_source=instagram&utm_medium=social&utm_campaign=flash+sale&utm_id=12&q=x",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	want := Campaign{Source: "instagram", Medium: "social", Name: "flash sale", Custom: map[string]string{"utm_id": "12"}}
+	got := ub.GetCampaign()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fail test GetCampaign() got %v want %v", got, want)
+	}
+}
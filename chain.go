@@ -0,0 +1,126 @@
+package uruki
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Chain fluent chainable layer on top of Builder. each step error collected, tagged with the step,
+// and returned once by Build
+type Chain struct {
+	ub   *Builder
+	step int
+	errs []error
+}
+
+// StepError error of a Chain step
+type StepError struct {
+	// Index: position of step on chain, From is 0
+	Index int
+	// Step: name of step, e.g. "Host" or name given to Do
+	Step string
+	// Err: underlying error
+	Err error
+}
+
+// Error message of step error
+func (se *StepError) Error() string {
+	return fmt.Sprintf("step %d %s: %v", se.Index, se.Step, se.Err)
+}
+
+// Unwrap underlying error of step
+func (se *StepError) Unwrap() error {
+	return se.Err
+}
+
+// From start chain from raw url with builder options merged like NewBuilder, URL of option replaced by raw
+func From(raw string, options ...Option) *Chain {
+	c := &Chain{}
+	opt, err := mergeOptions(options)
+	if err != nil {
+		c.errs = append(c.errs, &StepError{Index: 0, Step: "From", Err: &Error{Op: "From", Component: "option", Err: err}})
+		return c
+	}
+	opt.URL = raw
+	ub, err := NewBuilder(opt)
+	if err != nil {
+		c.errs = append(c.errs, &StepError{Index: 0, Step: "From", Err: err})
+		return c
+	}
+	c.ub = ub
+	return c
+}
+
+// Scheme set scheme, checked against restricted scheme of builder
+func (c *Chain) Scheme(scheme string) *Chain {
+	return c.Do("Scheme", func(ub *Builder) error {
+		scheme = strings.ToLower(strings.TrimSpace(scheme))
+		if s, _, ok := splitScheme(scheme + ":"); !ok || s != scheme {
+			return ErrorSchemeInvalid
+		}
+		if err := ub.checkScheme(scheme); err != nil {
+			return withOp("Scheme", scheme, err)
+		}
+		return ub.mutate("Scheme", []string{scheme}, func() error {
+			ub.url.Scheme = scheme
+			return nil
+		})
+	})
+}
+
+// Host set host with optional port, must be valid RFC 3986 host
+func (c *Chain) Host(host string) *Chain {
+	return c.Do("Host", func(ub *Builder) error {
+		if len(host) < 1 || strings.ContainsAny(host, "@/?#") || len(Validate("//"+host)) > 0 {
+			return ErrorHostInvalid
+		}
+		return ub.mutate("Host", []string{host}, func() error {
+			ub.url.Host = host
+			return nil
+		})
+	})
+}
+
+// Path set path from raw segments, see SetPathSegments
+func (c *Chain) Path(segments ...string) *Chain {
+	return c.Do("Path", func(ub *Builder) error {
+		ub.SetPathSegments(SetPathSegmentsOpt{Segments: segments})
+		return nil
+	})
+}
+
+// Query add query parameter with default space encoding of builder, see AddQueryParam
+func (c *Chain) Query(key, val string) *Chain {
+	return c.Do("Query", func(ub *Builder) error {
+		return ub.AddQueryParam(AddQueryParamOpt{Key: key, Val: val, UseDefaultEncode: true})
+	})
+}
+
+// Fragment set fragment with default space encoding of builder, see SetFragment
+func (c *Chain) Fragment(fragment string) *Chain {
+	return c.Do("Fragment", func(ub *Builder) error {
+		ub.SetFragment(SetFragmentOpt{Fragment: fragment, UseDefaultEncode: true})
+		return nil
+	})
+}
+
+// Do apply any Builder mutation as named step, e.g. SetCampaign or Sign
+func (c *Chain) Do(step string, fn func(ub *Builder) error) *Chain {
+	c.step++
+	if c.ub == nil {
+		return c
+	}
+	if err := fn(c.ub); err != nil {
+		c.errs = append(c.errs, &StepError{Index: c.step, Step: step, Err: err})
+	}
+	return c
+}
+
+// Build get the builder, or all step errors joined. each error is *StepError
+func (c *Chain) Build() (*Builder, error) {
+	if len(c.errs) > 0 {
+		return nil, errors.Join(c.errs...)
+	}
+	return c.ub, nil
+}
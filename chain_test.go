@@ -0,0 +1,92 @@
+package uruki
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_Chain(t *testing.T) {
+	ub, err := From("http://localhost/old?ref=app", Option{DefaultSpaceEncode: PercentTwentyEncoding}).
+		Scheme("HTTPS").
+		Host("www.tokopedia.com").
+		Path("search", "kopi susu").
+		Query("q", "kopi susu").
+		Fragment("top").
+		Do("Campaign", func(ub *Builder) error {
+			return ub.SetCampaign(Campaign{Source: "app", Medium: "push", Name: "flash"}, CampaignOptions{})
+		}).
+		Build()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	want := "https://www.tokopedia.com/search/kopi%20susu?ref=app&q=kopi%20susu&utm_source=app&utm_medium=push&utm_campaign=flash#top"
+	if got := ub.GetURLResult(); got != want {
+		t.Errorf("fail test Chain Build() got %v want %v", got, want)
+	}
+}
+
+func Test_ChainErrors(t *testing.T) {
+	type args struct {
+		name      string
+		chain     func() *Chain
+		wantSteps []string
+		wantErrs  []error
+	}
+
+	testCases := []args{
+		{
+			name: "errors accumulated with step",
+			chain: func() *Chain {
+				return From("https://www.tokopedia.com", Option{RestrictScheme: []string{"https"}}).
+					Scheme("http").
+					Host("www.toko pedia.com").
+					Path("search").
+					Query(" ", "kopi").
+					Fragment("top")
+			},
+			wantSteps: []string{"Scheme", "Host", "Query"},
+			wantErrs:  []error{ErrorInvalidSchemeURI, ErrorHostInvalid, ErrorKeyEmpty},
+		},
+		{
+			name: "invalid scheme syntax",
+			chain: func() *Chain {
+				return From("").Scheme("1http").Scheme("")
+			},
+			wantSteps: []string{"Scheme", "Scheme"},
+			wantErrs:  []error{ErrorSchemeInvalid, ErrorSchemeInvalid},
+		},
+		{
+			name: "from error skip other steps",
+			chain: func() *Chain {
+				return From("http://www.tokopedia.com", Option{RestrictScheme: []string{"https"}}).Host("x y")
+			},
+			wantSteps: []string{"From"},
+			wantErrs:  []error{ErrorInvalidSchemeURI},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ub, err := tt.chain().Build()
+			if ub != nil || err == nil {
+				t.Errorf("fail test Chain Build() got %v want error", ub)
+				return
+			}
+			joined, ok := err.(interface{ Unwrap() []error })
+			if !ok || len(joined.Unwrap()) != len(tt.wantSteps) {
+				t.Errorf("fail test Chain Build() got %v want %d errors", err, len(tt.wantSteps))
+				return
+			}
+			for i, v := range joined.Unwrap() {
+				se := &StepError{}
+				if !errors.As(v, &se) || se.Step != tt.wantSteps[i] || !errors.Is(v, tt.wantErrs[i]) {
+					t.Errorf("fail test Chain Build() got %v want step %v error %v", v, tt.wantSteps[i], tt.wantErrs[i])
+				}
+			}
+			if !errors.Is(err, tt.wantErrs[0]) {
+				t.Errorf("fail test Chain Build() got %v want %v", err, tt.wantErrs[0])
+			}
+		})
+	}
+}
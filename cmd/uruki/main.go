@@ -0,0 +1,492 @@
+// Command uruki inspect and edit urls from the shell with uruki builder.
+//
+//	uruki <command> [flags] [url...]
+//
+// urls read from arguments, or from stdin line by line when no url given. see usage for commands and exit codes
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/forderation/uruki"
+)
+
+const (
+	// exitOK command succeed
+	exitOK = 0
+	// exitFalse command succeed with negative result: diff found differences or get found no query key
+	exitFalse = 1
+	// exitUsage invalid command, flag or argument
+	exitUsage = 2
+	// exitInvalid url invalid or rejected by options
+	exitInvalid = 3
+)
+
+const usage = `usage: uruki <command> [flags] [url...]
+
+urls read from arguments, or from stdin line by line when no url given.
+
+commands:
+  parse [--json] [url...]                    print components as table or JSON
+  get <component|query key> [url...]         print component (scheme, user, host, hostname, port, path,
+                                             query, fragment) or value of query key, use query.<key> for
+                                             query key named as component
+  set [--query k=v] [--path p] [--fragment f] [url...]
+                                             replace query value in place or append it, path and fragment
+  del --query k [url...]                     delete query key
+  normalize [url...]                         normalize with WHATWG parser and repair encoding
+  resolve <base> <ref>                       resolve reference against base url
+  diff <a> <b>                               print differences of components
+
+flags of every command:
+  --restrict-scheme s   allowed schemes, comma separated or repeated
+  --space-encoding e    default space encoding: plus, %20 or none
+  --auto-escape         escape existing query of url
+
+exit codes:
+  0  success
+  1  diff found differences, get found no query key
+  2  invalid command, flag or argument
+  3  url invalid or rejected by options
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// command run by name with its flags, urls and output
+type command struct {
+	flags  *flag.FlagSet
+	option *uruki.Option
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// run execute command of args and return exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) < 1 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+	name := args[0]
+	cmd := newCommand(name, stdin, stdout, stderr)
+	switch name {
+	case "parse":
+		return cmd.parse(args[1:])
+	case "get":
+		return cmd.get(args[1:])
+	case "set":
+		return cmd.set(args[1:])
+	case "del":
+		return cmd.del(args[1:])
+	case "normalize":
+		return cmd.normalize(args[1:])
+	case "resolve":
+		return cmd.resolve(args[1:])
+	case "diff":
+		return cmd.diff(args[1:])
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	}
+	fmt.Fprintf(stderr, "uruki: unknown command %q\n\n%s", name, usage)
+	return exitUsage
+}
+
+// newCommand command with flags mirroring uruki.Option
+func newCommand(name string, stdin io.Reader, stdout, stderr io.Writer) *command {
+	cmd := &command{
+		flags:  flag.NewFlagSet("uruki "+name, flag.ContinueOnError),
+		option: &uruki.Option{},
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+	}
+	cmd.flags.SetOutput(stderr)
+	cmd.flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+	}
+	cmd.flags.Func("restrict-scheme", "allowed schemes, comma separated or repeated", func(s string) error {
+		for _, v := range strings.Split(s, ",") {
+			if v = strings.TrimSpace(v); len(v) > 0 {
+				cmd.option.RestrictScheme = append(cmd.option.RestrictScheme, v)
+			}
+		}
+		return nil
+	})
+	cmd.flags.Func("space-encoding", "default space encoding: plus, %20 or none", func(s string) error {
+		switch s {
+		case "plus", uruki.PlusEncoding:
+			cmd.option.DefaultSpaceEncode = uruki.PlusEncoding
+		case uruki.PercentTwentyEncoding:
+			cmd.option.DefaultSpaceEncode = uruki.PercentTwentyEncoding
+		case "none":
+			cmd.option.DefaultSpaceEncode = uruki.WithoutEncoding
+		default:
+			return fmt.Errorf("space encoding must be plus, %%20 or none")
+		}
+		return nil
+	})
+	cmd.flags.BoolVar(&cmd.option.UseEscapeAutomateURL, "auto-escape", false, "escape existing query of url")
+	return cmd
+}
+
+// parseFlags parse flags of command, false when flags invalid
+func (cmd *command) parseFlags(args []string) bool {
+	return cmd.flags.Parse(args) == nil
+}
+
+// builder create builder of raw url with options of flags
+func (cmd *command) builder(rawURL string) (*uruki.Builder, error) {
+	opt := *cmd.option
+	opt.URL = rawURL
+	return uruki.NewBuilder(opt)
+}
+
+// eachURL call fn for each url of arguments or stdin line, return exitInvalid when any url failed
+func (cmd *command) eachURL(urls []string, fn func(ub *uruki.Builder) error) int {
+	code := exitOK
+	handle := func(rawURL string) {
+		ub, err := cmd.builder(rawURL)
+		if err == nil {
+			err = fn(ub)
+		}
+		if err != nil {
+			fmt.Fprintln(cmd.stderr, err)
+			code = exitInvalid
+		}
+	}
+	if len(urls) > 0 {
+		for _, v := range urls {
+			handle(v)
+		}
+		return code
+	}
+	scanner := bufio.NewScanner(cmd.stdin)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); len(line) > 0 {
+			handle(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(cmd.stderr, err)
+		return exitInvalid
+	}
+	return code
+}
+
+// parse print components as table or JSON line
+func (cmd *command) parse(args []string) int {
+	asJSON := cmd.flags.Bool("json", false, "print components as JSON, one object per line")
+	if !cmd.parseFlags(args) {
+		return exitUsage
+	}
+	first := true
+	return cmd.eachURL(cmd.flags.Args(), func(ub *uruki.Builder) error {
+		c := ub.ToComponents()
+		if *asJSON {
+			data, err := json.Marshal(c)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.stdout, string(data))
+			return nil
+		}
+		if !first {
+			fmt.Fprintln(cmd.stdout)
+		}
+		first = false
+		u := ub.URL()
+		w := tabwriter.NewWriter(cmd.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(w, "url\t%s\n", u.String())
+		fmt.Fprintf(w, "scheme\t%s\n", u.Scheme())
+		if user := u.User(); user != nil {
+			fmt.Fprintf(w, "user\t%s\n", user.Username())
+		}
+		fmt.Fprintf(w, "host\t%s\n", c.Host)
+		fmt.Fprintf(w, "port\t%s\n", c.Port)
+		fmt.Fprintf(w, "path\t%s\n", u.Path())
+		for _, v := range c.Query {
+			fmt.Fprintf(w, "query\t%s=%s\n", v.Key, v.Value)
+		}
+		fmt.Fprintf(w, "fragment\t%s\n", u.Fragment())
+		return w.Flush()
+	})
+}
+
+// get print component or value of query key
+func (cmd *command) get(args []string) int {
+	if !cmd.parseFlags(args) {
+		return exitUsage
+	}
+	if cmd.flags.NArg() < 1 {
+		fmt.Fprintf(cmd.stderr, "uruki get: component or query key required\n\n%s", usage)
+		return exitUsage
+	}
+	name := cmd.flags.Arg(0)
+	found := true
+	code := cmd.eachURL(cmd.flags.Args()[1:], func(ub *uruki.Builder) error {
+		u := ub.URL()
+		switch name {
+		case "scheme":
+			fmt.Fprintln(cmd.stdout, u.Scheme())
+		case "user":
+			username := ""
+			if user := u.User(); user != nil {
+				username = user.Username()
+			}
+			fmt.Fprintln(cmd.stdout, username)
+		case "host":
+			fmt.Fprintln(cmd.stdout, u.Host())
+		case "hostname":
+			fmt.Fprintln(cmd.stdout, u.Hostname())
+		case "port":
+			fmt.Fprintln(cmd.stdout, u.Port())
+		case "path":
+			fmt.Fprintln(cmd.stdout, u.Path())
+		case "query":
+			fmt.Fprintln(cmd.stdout, u.RawQuery())
+		case "fragment":
+			fmt.Fprintln(cmd.stdout, u.Fragment())
+		default:
+			key := strings.TrimPrefix(name, "query.")
+			values, ok := ub.GetAllQueryValue()[key]
+			if !ok {
+				found = false
+				fmt.Fprintln(cmd.stdout)
+				return nil
+			}
+			fmt.Fprintln(cmd.stdout, values[0])
+		}
+		return nil
+	})
+	if code == exitOK && !found {
+		return exitFalse
+	}
+	return code
+}
+
+// queryFlag repeated k=v flag
+type queryFlag [][2]string
+
+// String see flag.Value
+func (q *queryFlag) String() string {
+	return fmt.Sprint(*q)
+}
+
+// Set see flag.Value
+func (q *queryFlag) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || len(strings.TrimSpace(k)) < 1 {
+		return errors.New("query must be key=value")
+	}
+	*q = append(*q, [2]string{k, v})
+	return nil
+}
+
+// set replace query value in place or append it, set path and fragment
+func (cmd *command) set(args []string) int {
+	var query queryFlag
+	cmd.flags.Var(&query, "query", "set query `key=value`, repeatable")
+	path := cmd.flags.String("path", "", "set path")
+	fragment := cmd.flags.String("fragment", "", "set fragment without '#'")
+	if !cmd.parseFlags(args) {
+		return exitUsage
+	}
+	set := map[string]bool{}
+	cmd.flags.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	return cmd.eachURL(cmd.flags.Args(), func(ub *uruki.Builder) error {
+		if set["path"] {
+			ub.SetPath(*path)
+		}
+		if set["fragment"] {
+			ub.SetFragment(uruki.SetFragmentOpt{Fragment: *fragment, EncodeSet: uruki.EncodeRFC3986})
+		}
+		if len(query) > 0 {
+			c := ub.ToComponents()
+			for _, kv := range query {
+				c.Query = setQueryEntry(c.Query, kv[0], kv[1], cmd.option.DefaultSpaceEncode)
+			}
+			opt := *cmd.option
+			opt.UseEscapeAutomateURL = false
+			edited, err := uruki.FromComponents(c, opt)
+			if err != nil {
+				return err
+			}
+			ub = edited
+		}
+		fmt.Fprintln(cmd.stdout, ub.GetURLResult())
+		return nil
+	})
+}
+
+// setQueryEntry replace value of the first entry of key and remove the others, append with space encoding when key not found
+func setQueryEntry(entries []uruki.QueryEntry, key, value, spaceEnc string) []uruki.QueryEntry {
+	result := make([]uruki.QueryEntry, 0, len(entries)+1)
+	replaced := false
+	for _, v := range entries {
+		if v.Key != key {
+			result = append(result, v)
+			continue
+		}
+		if !replaced {
+			v.Value = value
+			v.NoValue = false
+			result = append(result, v)
+			replaced = true
+		}
+	}
+	if !replaced {
+		result = append(result, uruki.QueryEntry{Key: key, Value: value, SpaceEnc: spaceEnc})
+	}
+	return result
+}
+
+// del delete query keys
+func (cmd *command) del(args []string) int {
+	var keys []string
+	cmd.flags.Func("query", "delete query `key`, repeatable", func(s string) error {
+		keys = append(keys, s)
+		return nil
+	})
+	if !cmd.parseFlags(args) {
+		return exitUsage
+	}
+	if len(keys) < 1 {
+		fmt.Fprintf(cmd.stderr, "uruki del: --query required\n\n%s", usage)
+		return exitUsage
+	}
+	return cmd.eachURL(cmd.flags.Args(), func(ub *uruki.Builder) error {
+		for _, k := range keys {
+			ub.DeleteKeyQuery(k)
+		}
+		fmt.Fprintln(cmd.stdout, ub.GetURLResult())
+		return nil
+	})
+}
+
+// normalize parse url with WHATWG parser and repair encoding, double encoded sequences kept
+func (cmd *command) normalize(args []string) int {
+	if !cmd.parseFlags(args) {
+		return exitUsage
+	}
+	cmd.option.ParseMode = uruki.WHATWG
+	return cmd.eachURL(cmd.flags.Args(), func(ub *uruki.Builder) error {
+		ub.RepairEncoding(uruki.RepairOptions{})
+		fmt.Fprintln(cmd.stdout, ub.GetURLResult())
+		return nil
+	})
+}
+
+// resolve resolve reference against base url, result checked with options
+func (cmd *command) resolve(args []string) int {
+	if !cmd.parseFlags(args) {
+		return exitUsage
+	}
+	if cmd.flags.NArg() != 2 {
+		fmt.Fprintf(cmd.stderr, "uruki resolve: base and ref required\n\n%s", usage)
+		return exitUsage
+	}
+	base, err := url.Parse(cmd.flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(cmd.stderr, err)
+		return exitInvalid
+	}
+	ref, err := url.Parse(cmd.flags.Arg(1))
+	if err != nil {
+		fmt.Fprintln(cmd.stderr, err)
+		return exitInvalid
+	}
+	return cmd.eachURL([]string{base.ResolveReference(ref).String()}, func(ub *uruki.Builder) error {
+		fmt.Fprintln(cmd.stdout, ub.GetURLResult())
+		return nil
+	})
+}
+
+// diff print differences of components, "~ component: a -> b" for changed and "-" / "+" for query only in a / b
+func (cmd *command) diff(args []string) int {
+	if !cmd.parseFlags(args) {
+		return exitUsage
+	}
+	if cmd.flags.NArg() != 2 {
+		fmt.Fprintf(cmd.stderr, "uruki diff: two urls required\n\n%s", usage)
+		return exitUsage
+	}
+	components := make([]uruki.Components, 0, 2)
+	paths := make([]string, 0, 2)
+	if code := cmd.eachURL(cmd.flags.Args(), func(ub *uruki.Builder) error {
+		components = append(components, ub.ToComponents())
+		paths = append(paths, ub.URL().Path())
+		return nil
+	}); code != exitOK {
+		return code
+	}
+	a, b := components[0], components[1]
+	lines := make([]string, 0)
+	changed := func(name, x, y string) {
+		if x != y {
+			lines = append(lines, fmt.Sprintf("~ %s: %s -> %s", name, x, y))
+		}
+	}
+	changed("scheme", a.Scheme, b.Scheme)
+	changed("user", username(a), username(b))
+	changed("host", a.Host, b.Host)
+	changed("port", a.Port, b.Port)
+	changed("path", paths[0], paths[1])
+	lines = append(lines, diffQuery(a.Query, b.Query)...)
+	changed("fragment", a.Fragment, b.Fragment)
+	for _, v := range lines {
+		fmt.Fprintln(cmd.stdout, v)
+	}
+	if len(lines) > 0 {
+		return exitFalse
+	}
+	return exitOK
+}
+
+// username username of components, empty without userinfo
+func username(c uruki.Components) string {
+	if c.Userinfo == nil {
+		return ""
+	}
+	return c.Userinfo.Username
+}
+
+// diffQuery decoded query parameters only in a as "-" and only in b as "+", order of query ignored
+func diffQuery(a, b []uruki.QueryEntry) []string {
+	count := map[string]int{}
+	for _, v := range a {
+		count[v.Key+"="+v.Value]++
+	}
+	for _, v := range b {
+		count[v.Key+"="+v.Value]--
+	}
+	params := make([]string, 0, len(count))
+	for k := range count {
+		params = append(params, k)
+	}
+	sort.Strings(params)
+	lines := make([]string, 0)
+	for _, k := range params {
+		for i := count[k]; i > 0; i-- {
+			lines = append(lines, "- query: "+k)
+		}
+		for i := count[k]; i < 0; i++ {
+			lines = append(lines, "+ query: "+k)
+		}
+	}
+	return lines
+}
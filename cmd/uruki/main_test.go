@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_Run(t *testing.T) {
+	type args struct {
+		name       string
+		args       []string
+		stdin      string
+		want       string
+		wantStderr string
+		wantCode   int
+	}
+
+	rawURL := "https://www.tokopedia.com/search?q=macbook+air&st=product#review"
+	testCases := []args{
+		{
+			name: "parse table",
+			args: []string{"parse", "https://user@www.tokopedia.com:8080/search?q=macbook+air#review"},
+			want: "url       https://user@www.tokopedia.com:8080/search?q=macbook+air#review\n" +
+				"scheme    https\nuser      user\nhost      www.tokopedia.com\nport      8080\npath      /search\n" +
+				"query     q=macbook air\nfragment  review\n",
+		},
+		{
+			name: "parse json from stdin",
+			args: []string{"parse", "--json"},
+			stdin: "https://www.tokopedia.com/a?q=1\n\n" +
+				"tokopedia://home\n",
+			want: `{"scheme":"https","host":"www.tokopedia.com","segments":["a"],"query":[{"key":"q","value":"1"}]}` + "\n" +
+				`{"scheme":"tokopedia","host":"home"}` + "\n",
+		},
+		{
+			name: "get component",
+			args: []string{"get", "host", rawURL},
+			want: "www.tokopedia.com\n",
+		},
+		{
+			name: "get query key",
+			args: []string{"get", "q", rawURL},
+			want: "macbook air\n",
+		},
+		{
+			name: "get query key named as component",
+			args: []string{"get", "query.host", "https://www.tokopedia.com/?host=m.tokopedia.com"},
+			want: "m.tokopedia.com\n",
+		},
+		{
+			name:     "get missing query key",
+			args:     []string{"get", "page", rawURL},
+			want:     "\n",
+			wantCode: exitFalse,
+		},
+		{
+			name: "set",
+			args: []string{"set", "--query", "q=macbook pro", "--query", "page=2", "--path", "/discovery", "--fragment", "top", rawURL},
+			want: "https://www.tokopedia.com/discovery?q=macbook+pro&st=product&page=2#top\n",
+		},
+		{
+			name: "set with space encoding",
+			args: []string{"set", "--space-encoding", "%20", "--query", "caption=gopaylater - cicil", "tokopedia://webview"},
+			want: "tokopedia://webview?caption=gopaylater%20-%20cicil\n",
+		},
+		{
+			name: "del",
+			args: []string{"del", "--query", "st", "--query", "q", rawURL},
+			want: "https://www.tokopedia.com/search#review\n",
+		},
+		{
+			name: "del keep other parameters",
+			args: []string{"del", "--query", "page", "https://x.com/dl?token=YWJj==&debug&page=1"},
+			want: "https://x.com/dl?token=YWJj==&debug\n",
+		},
+		{
+			name: "normalize",
+			args: []string{"normalize", "HTTPS://WWW.Tokopedia.COM:443/a/../b?x=%zz"},
+			want: "https://www.tokopedia.com/b?x=%25zz\n",
+		},
+		{
+			name: "resolve",
+			args: []string{"resolve", "https://www.tokopedia.com/a/b", "../c?q=1"},
+			want: "https://www.tokopedia.com/c?q=1\n",
+		},
+		{
+			name:     "resolve restricted scheme",
+			args:     []string{"resolve", "--restrict-scheme", "https,tokopedia", "https://www.tokopedia.com/a", "http://evil.com"},
+			wantCode: exitInvalid,
+			wantStderr: `uruki: NewBuilder scheme "http": invalid scheme url not in restricted schemes [https tokopedia]` +
+				"\n",
+		},
+		{
+			name: "diff",
+			args: []string{"diff", "https://www.tokopedia.com/a?q=1&p=2", "https://m.tokopedia.com/a?p=3&q=1#x"},
+			want: "~ host: www.tokopedia.com -> m.tokopedia.com\n- query: p=2\n+ query: p=3\n" +
+				"~ fragment:  -> x\n",
+			wantCode: exitFalse,
+		},
+		{
+			name: "diff same",
+			args: []string{"diff", rawURL, rawURL},
+		},
+		{
+			name:       "invalid url continue other lines",
+			args:       []string{"get", "--restrict-scheme", "https", "scheme"},
+			stdin:      "http://www.tokopedia.com\nhttps://www.tokopedia.com\n",
+			want:       "https\n",
+			wantStderr: `uruki: NewBuilder scheme "http": invalid scheme url not in restricted schemes [https]` + "\n",
+			wantCode:   exitInvalid,
+		},
+		{
+			name:     "unknown command",
+			args:     []string{"encode"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "invalid space encoding",
+			args:     []string{"parse", "--space-encoding", "tab", rawURL},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "del without query",
+			args:     []string{"del", rawURL},
+			wantCode: exitUsage,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Errorf("fail test run() code got %v want %v, stderr %v", code, tt.wantCode, stderr.String())
+			}
+			if got := stdout.String(); got != tt.want {
+				t.Errorf("fail test run() got %q want %q", got, tt.want)
+			}
+			if len(tt.wantStderr) > 0 && stderr.String() != tt.wantStderr {
+				t.Errorf("fail test run() stderr got %q want %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
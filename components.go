@@ -0,0 +1,221 @@
+package uruki
+
+import (
+	"net/netip"
+	"net/url"
+	"strings"
+)
+
+// Components structured form of url to be edited as form, round trip of ToComponents and FromComponents is lossless.
+// host written in brackets only when it is IPv6 address, so reg-name host with ':' kept as is, e.g. ws://a::
+// raw fields keep original encoding and only set when it differ from default encoding, raw used as is while it still
+// decoded into the same value, so edited value encoded again
+type Components struct {
+	Scheme       string       `json:"scheme,omitempty" jsonschema_description:"scheme without ':', e.g. https"`
+	Opaque       string       `json:"opaque,omitempty" jsonschema_description:"encoded opaque data of url without authority and path, e.g. cs@tokopedia.com of mailto:cs@tokopedia.com"`
+	Userinfo     *Userinfo    `json:"userinfo,omitempty" jsonschema_description:"username and optional password"`
+	Host         string       `json:"host,omitempty" jsonschema_description:"host name or IP address without port, IPv6 without brackets"`
+	Port         string       `json:"port,omitempty" jsonschema_description:"port without ':'"`
+	EmptyPort    bool         `json:"emptyPort,omitempty" jsonschema_description:"host written with ':' without port, e.g. ws://a:"`
+	OmitHost     bool         `json:"omitHost,omitempty" jsonschema_description:"url written without '//' when host is empty, e.g. file:/etc/hosts"`
+	Segments     []string     `json:"segments,omitempty" jsonschema_description:"decoded path segments, path [\"product\", \"123\"] written as /product/123"`
+	RelativePath bool         `json:"relativePath,omitempty" jsonschema_description:"path written without leading '/'"`
+	RawPath      string       `json:"rawPath,omitempty" jsonschema_description:"original encoded path, used while it decoded into segments"`
+	Query        []QueryEntry `json:"query,omitempty" jsonschema_description:"query parameters in order"`
+	ForceQuery   bool         `json:"forceQuery,omitempty" jsonschema_description:"url written with '?' even without query"`
+	Fragment     string       `json:"fragment,omitempty" jsonschema_description:"decoded fragment without '#'"`
+	RawFragment  string       `json:"rawFragment,omitempty" jsonschema_description:"original encoded fragment, used while it decoded into fragment"`
+}
+
+// Userinfo username and password of Components
+type Userinfo struct {
+	Username string  `json:"username" jsonschema_description:"decoded username"`
+	Password *string `json:"password,omitempty" jsonschema_description:"decoded password, absent when url has no password"`
+}
+
+// QueryEntry query parameter of Components
+type QueryEntry struct {
+	Key      string `json:"key" jsonschema_description:"decoded key"`
+	Value    string `json:"value" jsonschema_description:"decoded value"`
+	NoValue  bool   `json:"noValue,omitempty" jsonschema_description:"parameter written without '=', e.g. ?debug"`
+	SpaceEnc string `json:"spaceEnc,omitempty" jsonschema_description:"space encoding of parameter, '+' (default) or '%20'"`
+	Raw      string `json:"raw,omitempty" jsonschema_description:"original encoded parameter, used while it decoded into key and value"`
+}
+
+// ToComponents structured form of url, see Components
+func (ub *Builder) ToComponents() Components {
+	uri := ub.url
+	c := Components{
+		Scheme:     uri.Scheme,
+		Opaque:     uri.Opaque,
+		Host:       uri.Hostname(),
+		Port:       uri.Port(),
+		EmptyPort:  strings.HasSuffix(uri.Host, ":"),
+		OmitHost:   uri.OmitHost,
+		ForceQuery: uri.ForceQuery,
+		Fragment:   uri.Fragment,
+	}
+	if uri.User != nil {
+		c.Userinfo = &Userinfo{Username: uri.User.Username()}
+		if password, ok := uri.User.Password(); ok {
+			c.Userinfo.Password = &password
+		}
+	}
+
+	if rawPath := uri.EscapedPath(); len(rawPath) > 0 {
+		c.RelativePath = !strings.HasPrefix(rawPath, "/")
+		c.Segments = splitPathSegments(rawPath)
+		if rawPath != c.escapedPath() {
+			c.RawPath = rawPath
+		}
+	}
+
+	if len(uri.RawQuery) > 0 {
+		for _, raw := range strings.Split(uri.RawQuery, ampersandStr) {
+			c.Query = append(c.Query, newQueryEntry(raw))
+		}
+	}
+
+	if rawFragment := uri.EscapedFragment(); rawFragment != (&url.URL{Fragment: uri.Fragment}).EscapedFragment() {
+		c.RawFragment = rawFragment
+	}
+	return c
+}
+
+// FromComponents create builder from structured form of url, url checked with options. see Components & NewBuilder
+func FromComponents(c Components, options ...Option) (*Builder, error) {
+	opt, err := mergeOptions(options)
+	if err != nil {
+		return nil, &Error{Op: "FromComponents", Component: "option", Err: err}
+	}
+	opt.URL = c.String()
+	return NewBuilder(opt)
+}
+
+// String encoded url of components
+func (c Components) String() string {
+	uri := &url.URL{
+		Scheme:     c.Scheme,
+		Opaque:     c.Opaque,
+		OmitHost:   c.OmitHost,
+		ForceQuery: c.ForceQuery,
+		Fragment:   c.Fragment,
+	}
+	if c.Userinfo != nil {
+		uri.User = url.User(c.Userinfo.Username)
+		if c.Userinfo.Password != nil {
+			uri.User = url.UserPassword(c.Userinfo.Username, *c.Userinfo.Password)
+		}
+	}
+
+	uri.Host = c.Host
+	if addr, err := netip.ParseAddr(c.Host); err == nil && addr.Is6() {
+		uri.Host = "[" + c.Host + "]"
+	}
+	if len(c.Port) > 0 || c.EmptyPort {
+		uri.Host += ":" + c.Port
+	}
+
+	rawPath := c.escapedPath()
+	if len(c.RawPath) > 0 && c.RelativePath == !strings.HasPrefix(c.RawPath, "/") &&
+		equalSegments(splitPathSegments(c.RawPath), c.Segments) {
+		rawPath = c.RawPath
+	}
+	if path, err := url.PathUnescape(rawPath); err == nil {
+		uri.Path = path
+		uri.RawPath = rawPath
+	}
+
+	query := make([]string, len(c.Query))
+	for i, v := range c.Query {
+		query[i] = v.escaped()
+	}
+	uri.RawQuery = strings.Join(query, ampersandStr)
+
+	if len(c.RawFragment) > 0 {
+		if fragment, err := url.PathUnescape(c.RawFragment); err == nil && fragment == c.Fragment {
+			uri.RawFragment = c.RawFragment
+		}
+	}
+	return uri.String()
+}
+
+// escapedPath path of segments with default encoding, '/' inside segment escaped
+func (c Components) escapedPath() string {
+	if len(c.Segments) < 1 {
+		return ""
+	}
+	segments := make([]string, len(c.Segments))
+	for i, v := range c.Segments {
+		segments[i] = strings.ReplaceAll((&url.URL{Path: v}).EscapedPath(), "/", "%2F")
+	}
+	rawPath := strings.Join(segments, "/")
+	if !c.RelativePath {
+		rawPath = "/" + rawPath
+	}
+	return rawPath
+}
+
+// splitPathSegments split encoded path into decoded segments without leading '/'
+func splitPathSegments(rawPath string) []string {
+	segments := strings.Split(strings.TrimPrefix(rawPath, "/"), "/")
+	for i, v := range segments {
+		if segment, err := url.PathUnescape(v); err == nil {
+			segments[i] = segment
+		}
+	}
+	return segments
+}
+
+// equalSegments check both segments are equal
+func equalSegments(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// newQueryEntry query entry of encoded query parameter, space encoding detected from parameter
+func newQueryEntry(raw string) QueryEntry {
+	q, v, ok := strings.Cut(raw, "=")
+	entry := QueryEntry{Key: q, Value: v, NoValue: !ok}
+	if key, err := url.QueryUnescape(q); err == nil {
+		entry.Key = key
+	}
+	if val, err := url.QueryUnescape(v); err == nil {
+		entry.Value = val
+	}
+	if strings.Contains(raw, PercentTwentyEncoding) && !strings.Contains(raw, PlusEncoding) {
+		entry.SpaceEnc = PercentTwentyEncoding
+	}
+	if raw != entry.escaped() {
+		entry.Raw = raw
+	}
+	return entry
+}
+
+// escaped encoded query parameter, raw used while it decoded into key and value
+func (e QueryEntry) escaped() string {
+	if len(e.Raw) > 0 && newQueryEntry(e.Raw).sameParam(e) {
+		return e.Raw
+	}
+	spaceEnc := e.SpaceEnc
+	if len(spaceEnc) < 1 {
+		spaceEnc = PlusEncoding
+	}
+	raw := escapeQuery(e.Key, spaceEnc)
+	if !e.NoValue {
+		raw += "=" + escapeQuery(e.Value, spaceEnc)
+	}
+	return raw
+}
+
+// sameParam check both entries are the same parameter regardless of encoding
+func (e QueryEntry) sameParam(other QueryEntry) bool {
+	return e.Key == other.Key && e.Value == other.Value && e.NoValue == other.NoValue
+}
@@ -0,0 +1,167 @@
+package uruki
+
+import (
+	"net/url"
+	"strings"
+)
+
+// EncodeSet percent-encode set standard used to escape url components
+type EncodeSet int
+
+// constants of EncodeSet
+const (
+	// EncodeDefault follow builder EncodeSet on per call options, EncodeLegacy on builder
+	EncodeDefault EncodeSet = iota
+	// EncodeLegacy url.QueryEscape then replace space encoding, behaviour before EncodeSet introduced
+	EncodeLegacy
+	// EncodeRFC3986 escape only characters not allowed on the component by RFC 3986, space as %20
+	EncodeRFC3986
+	// EncodeWHATWG escape with WHATWG URL Standard percent-encode set of the component,
+	// query key and value use application/x-www-form-urlencoded with space as +
+	EncodeWHATWG
+)
+
+// URLComponent component of url to escape
+type URLComponent int
+
+// constants of URLComponent
+const (
+	ComponentUserinfo URLComponent = iota + 1
+	ComponentPathSegment
+	ComponentQueryKey
+	ComponentQueryValue
+	ComponentFragment
+)
+
+// Encoder percent encoder of raw (decoded) url component value
+type Encoder struct {
+	// Set: percent-encode set, default EncodeLegacy
+	Set EncodeSet
+	// SpaceEnc: space encoding, default depend on Set. see SpaceEncoding const for more the details
+	SpaceEnc string
+}
+
+// Escape percent encode raw value of component, '%' always escaped. net/url may escape more characters on path,
+// userinfo and fragment while serializing
+func (e Encoder) Escape(raw string, component URLComponent) string {
+	switch e.Set {
+	case EncodeRFC3986:
+		return percentEncode(raw, rfc3986Allowed(component), e.spaceEnc(PercentTwentyEncoding), false)
+	case EncodeWHATWG:
+		if component == ComponentQueryKey || component == ComponentQueryValue {
+			return percentEncode(raw, whatwgFormAllowed, e.spaceEnc(PlusEncoding), false)
+		}
+		return percentEncode(raw, whatwgAllowed(component), e.spaceEnc(PercentTwentyEncoding), false)
+	}
+	switch component {
+	case ComponentQueryKey, ComponentQueryValue, ComponentFragment:
+		return strings.ReplaceAll(url.QueryEscape(raw), PlusEncoding, e.spaceEnc(PlusEncoding))
+	}
+	return strings.ReplaceAll(url.PathEscape(raw), PercentTwentyEncoding, e.spaceEnc(PercentTwentyEncoding))
+}
+
+// spaceEnc space encoding of encoder, fallback to given default
+func (e Encoder) spaceEnc(defaultEnc string) string {
+	if len(e.SpaceEnc) > 0 {
+		return e.SpaceEnc
+	}
+	return defaultEnc
+}
+
+// encoder get encoder of builder for per call set & space encoding
+func (ub *Builder) encoder(set EncodeSet, spaceEnc string) Encoder {
+	if set == EncodeDefault {
+		set = ub.encodeSet
+	}
+	if set == EncodeDefault {
+		set = EncodeLegacy
+	}
+	return Encoder{Set: set, SpaceEnc: spaceEnc}
+}
+
+// rfc3986Allowed characters allowed as is on component by RFC 3986
+func rfc3986Allowed(component URLComponent) func(c byte) bool {
+	return func(c byte) bool {
+		if isUnreserved(c) {
+			return true
+		}
+		switch component {
+		case ComponentUserinfo:
+			// sub-delims, ':' escaped since it separate username and password
+			return strings.IndexByte("!$&'()*+,;=", c) >= 0
+		case ComponentPathSegment:
+			return strings.IndexByte("!$&'()*+,;=:@", c) >= 0
+		case ComponentQueryKey:
+			// '&', '=' and '+' escaped since they have meaning on query
+			return strings.IndexByte("!$'()*,;:@/?", c) >= 0
+		case ComponentQueryValue:
+			return strings.IndexByte("!$'()*,;=:@/?", c) >= 0
+		case ComponentFragment:
+			return strings.IndexByte("!$&'()*+,;=:@/?", c) >= 0
+		}
+		return false
+	}
+}
+
+// whatwgAllowed characters not in WHATWG percent-encode set of component
+func whatwgAllowed(component URLComponent) func(c byte) bool {
+	return func(c byte) bool {
+		if c <= ' ' || c >= 0x7f || c == '%' {
+			return false
+		}
+		// fragment percent-encode set
+		if strings.IndexByte("\"<>`", c) >= 0 {
+			return false
+		}
+		if component == ComponentFragment {
+			return true
+		}
+		// path percent-encode set, '/' escaped since it is segment
+		if strings.IndexByte("#?{}^/", c) >= 0 {
+			return false
+		}
+		if component == ComponentPathSegment {
+			return true
+		}
+		// userinfo percent-encode set
+		return strings.IndexByte(":;=@[\\]|", c) < 0
+	}
+}
+
+// whatwgFormAllowed characters not in application/x-www-form-urlencoded percent-encode set
+func whatwgFormAllowed(c byte) bool {
+	return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("*-._", c) >= 0
+}
+
+// percentEncode escape bytes not allowed with uppercase hex, space replaced by spaceEnc.
+// keepEscape keep existing valid escape sequence for already encoded input
+func percentEncode(s string, allowed func(c byte) bool, spaceEnc string, keepEscape bool) string {
+	const hexUpper = "0123456789ABCDEF"
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case keepEscape && c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			sb.WriteByte(c)
+		case c == ' ' && len(spaceEnc) > 0:
+			sb.WriteString(spaceEnc)
+		case allowed(c):
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte('%')
+			sb.WriteByte(hexUpper[c>>4])
+			sb.WriteByte(hexUpper[c&15])
+		}
+	}
+	return sb.String()
+}
+
+// isUnreserved check RFC 3986 unreserved character
+func isUnreserved(c byte) bool {
+	return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// isHex check hexadecimal digit
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
@@ -0,0 +1,259 @@
+package uruki
+
+import (
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// EncodingIssueKind kind of encoding issue found by EncodingReport
+type EncodingIssueKind int
+
+// constants of EncodingIssueKind
+const (
+	// IssueDoubleEncoded escape sequence encoded twice or more, e.g. "%2520"
+	IssueDoubleEncoded EncodingIssueKind = iota + 1
+	// IssueInvalidEscape '%' not followed by two hex digits, e.g. "100%"
+	IssueInvalidEscape
+	// IssueRawReserved character not allowed raw on the component by RFC 3986, e.g. space, '|' or non ASCII
+	IssueRawReserved
+	// IssueMixedSpace query space encoded as both "+" and "%20", reported on the one differ from the first
+	IssueMixedSpace
+)
+
+// String name of encoding issue kind
+func (k EncodingIssueKind) String() string {
+	switch k {
+	case IssueDoubleEncoded:
+		return "double-encoded"
+	case IssueInvalidEscape:
+		return "invalid-escape"
+	case IssueRawReserved:
+		return "raw-reserved"
+	case IssueMixedSpace:
+		return "mixed-space"
+	}
+	return "unknown"
+}
+
+// EncodingIssue encoding issue of url component
+type EncodingIssue struct {
+	// Kind: kind of issue
+	Kind EncodingIssueKind
+	// Component: url component of issue, one of path, query, fragment
+	Component string
+	// Offset: byte offset of sequence on escaped component
+	Offset int
+	// Sequence: offending sequence, e.g. "%2520", "%", " " or "+"
+	Sequence string
+}
+
+// RepairOptions options for RepairEncoding
+type RepairOptions struct {
+	// SpaceEnc: query space encoding after repair, PlusEncoding or PercentTwentyEncoding.
+	// default follow the first space encoding of query, otherwise DefaultSpaceEncode of builder, otherwise %20
+	SpaceEnc string
+	// DecodeDoubleEncoded: decode double encoded sequence until encoded once. default keep it, "%2520" can be
+	// an encoded literal "%20" or a nested url intentionally encoded twice
+	DecodeDoubleEncoded bool
+}
+
+// encodingComponent escaped url component to scan and repair
+type encodingComponent struct {
+	name    string
+	escaped string
+	allowed func(c byte) bool
+}
+
+// EncodingReport find double encoded sequences, invalid escapes, raw reserved characters and mixed space encodings
+// on escaped path, query and fragment in order
+func (ub *Builder) EncodingReport() []EncodingIssue {
+	issues := make([]EncodingIssue, 0)
+	for _, component := range ub.encodingComponents() {
+		issues = append(issues, scanEncoding(component)...)
+	}
+	return issues
+}
+
+// RepairEncoding fix issues of EncodingReport and return the repaired issues. invalid '%' escaped as %25, raw characters escaped, query spaces unified and double encoded sequences decoded when DecodeDoubleEncoded.
+// repairing twice is no-op.
+// empty when the change vetoed by hook
+func (ub *Builder) RepairEncoding(opt RepairOptions) []EncodingIssue {
+	issues := make([]EncodingIssue, 0)
+	for _, issue := range ub.EncodingReport() {
+		if opt.DecodeDoubleEncoded || issue.Kind != IssueDoubleEncoded {
+			issues = append(issues, issue)
+		}
+	}
+	if len(issues) < 1 {
+		return issues
+	}
+	err := ub.mutate("RepairEncoding", []string{opt.SpaceEnc}, func() error {
+		for _, component := range ub.encodingComponents() {
+			switch component.name {
+			case "path":
+				rawPath := repairEncoding(component, PercentTwentyEncoding, opt.DecodeDoubleEncoded)
+				if path, err := url.PathUnescape(rawPath); err == nil {
+					ub.url.Path = path
+					ub.url.RawPath = rawPath
+				}
+			case "query":
+				ub.url.RawQuery = repairEncoding(component, ub.repairSpaceEnc(opt.SpaceEnc), opt.DecodeDoubleEncoded)
+			case "fragment":
+				rawFragment := repairEncoding(component, PercentTwentyEncoding, opt.DecodeDoubleEncoded)
+				if fragment, err := url.PathUnescape(rawFragment); err == nil {
+					ub.url.Fragment = fragment
+					ub.url.RawFragment = rawFragment
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return make([]EncodingIssue, 0)
+	}
+	return issues
+}
+
+// encodingComponents escaped path, query and fragment with characters allowed raw by RFC 3986
+func (ub *Builder) encodingComponents() []encodingComponent {
+	return []encodingComponent{
+		{"path", ub.url.EscapedPath(), func(c byte) bool {
+			return isUnreserved(c) || strings.IndexByte(rfc3986PChar+"/", c) >= 0
+		}},
+		{"query", ub.url.RawQuery, func(c byte) bool {
+			return isUnreserved(c) || strings.IndexByte(rfc3986PChar+"/?", c) >= 0
+		}},
+		{"fragment", ub.url.EscapedFragment(), func(c byte) bool {
+			return isUnreserved(c) || strings.IndexByte(rfc3986PChar+"/?", c) >= 0
+		}},
+	}
+}
+
+// repairSpaceEnc query space encoding used to repair
+func (ub *Builder) repairSpaceEnc(spaceEnc string) string {
+	if spaceEnc == PlusEncoding || spaceEnc == PercentTwentyEncoding {
+		return spaceEnc
+	}
+	if first := firstQuerySpace(ub.url.RawQuery); len(first) > 0 {
+		return first
+	}
+	if ub.defaultSpaceEncode == PlusEncoding {
+		return PlusEncoding
+	}
+	return PercentTwentyEncoding
+}
+
+// firstQuerySpace first space encoding of raw query, "+" or "%20"
+func firstQuerySpace(rawQuery string) string {
+	plus := strings.IndexByte(rawQuery, '+')
+	percent := strings.Index(rawQuery, PercentTwentyEncoding)
+	switch {
+	case plus >= 0 && (percent < 0 || plus < percent):
+		return PlusEncoding
+	case percent >= 0:
+		return PercentTwentyEncoding
+	}
+	return ""
+}
+
+// scanEncoding find encoding issues of escaped component
+func scanEncoding(component encodingComponent) []EncodingIssue {
+	issues := make([]EncodingIssue, 0)
+	s := component.escaped
+	firstSpace := ""
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		issue := EncodingIssue{Component: component.name, Offset: i}
+		switch {
+		case c == '%' && doubleEncodedLen(s[i:]) > 0:
+			issue.Kind, issue.Sequence = IssueDoubleEncoded, s[i:i+doubleEncodedLen(s[i:])]
+		case c == '%' && (i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2])):
+			issue.Kind, issue.Sequence = IssueInvalidEscape, "%"
+		case component.name == "query" && (c == '+' || strings.HasPrefix(s[i:], PercentTwentyEncoding)):
+			space := PercentTwentyEncoding
+			if c == '+' {
+				space = PlusEncoding
+			}
+			if len(firstSpace) < 1 {
+				firstSpace = space
+			}
+			if space == firstSpace {
+				i += len(space) - 1
+				continue
+			}
+			issue.Kind, issue.Sequence = IssueMixedSpace, space
+		case c == '%' || component.allowed(c):
+			continue
+		default:
+			_, size := utf8.DecodeRuneInString(s[i:])
+			issue.Kind, issue.Sequence = IssueRawReserved, s[i:i+size]
+		}
+		issues = append(issues, issue)
+		i += len(issue.Sequence) - 1
+	}
+	return issues
+}
+
+// repairEncoding fix encoding issues of escaped component, query space encoded with spaceEnc
+func repairEncoding(component encodingComponent, spaceEnc string, decodeDouble bool) string {
+	s := component.escaped
+	for decodeDouble {
+		decoded := decodeDoubleEncoded(s)
+		if decoded == s {
+			break
+		}
+		s = decoded
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			if component.name == "query" && s[i:i+3] == PercentTwentyEncoding {
+				sb.WriteString(spaceEnc)
+			} else {
+				sb.WriteString(s[i : i+3])
+			}
+			i += 2
+		case c == '+' && component.name == "query":
+			sb.WriteString(spaceEnc)
+		case c == ' ':
+			sb.WriteString(spaceEnc)
+		case c != '%' && component.allowed(c):
+			sb.WriteByte(c)
+		default:
+			sb.WriteString(percentEncode(s[i:i+1], func(c byte) bool { return false }, "", false))
+		}
+	}
+	return sb.String()
+}
+
+// doubleEncodedLen length of "%25" followed by any "25" then two hex digits at start of s, 0 when not double encoded
+func doubleEncodedLen(s string) int {
+	if !strings.HasPrefix(s, "%25") {
+		return 0
+	}
+	n := 3
+	for strings.HasPrefix(s[n:], "25") && n+3 < len(s) && isHex(s[n+2]) && isHex(s[n+3]) {
+		n += 2
+	}
+	if n+1 >= len(s) || !isHex(s[n]) || !isHex(s[n+1]) {
+		return 0
+	}
+	return n + 2
+}
+
+// decodeDoubleEncoded decode one layer of double encoded sequences
+func decodeDoubleEncoded(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if n := doubleEncodedLen(s[i:]); n > 0 {
+			sb.WriteString("%" + s[i+3:i+n])
+			i += n - 1
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
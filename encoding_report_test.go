@@ -0,0 +1,129 @@
+package uruki
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_EncodingReport(t *testing.T) {
+	type args struct {
+		name string
+		url  string
+		want []EncodingIssue
+	}
+
+	testCases := []args{
+		{
+			name: "clean url",
+			url:  "https://www.tokopedia.com/search?q=macbook+air&brand=apple#top",
+			want: []EncodingIssue{},
+		},
+		{
+			name: "double encoded path and query",
+			url:  "https://www.tokopedia.com/kopi%2520susu?q=p%2526g&r=%25252F",
+			want: []EncodingIssue{
+				{Kind: IssueDoubleEncoded, Component: "path", Offset: 5, Sequence: "%2520"},
+				{Kind: IssueDoubleEncoded, Component: "query", Offset: 3, Sequence: "%2526"},
+				{Kind: IssueDoubleEncoded, Component: "query", Offset: 12, Sequence: "%25252F"},
+			},
+		},
+		{
+			name: "invalid escape, raw reserved and mixed space on query",
+			url:  "https://www.tokopedia.com/search?q=macbook+air%20m2&disc=100%&tag=a|b c&kopi=☕",
+			want: []EncodingIssue{
+				{Kind: IssueMixedSpace, Component: "query", Offset: 13, Sequence: "%20"},
+				{Kind: IssueInvalidEscape, Component: "query", Offset: 27, Sequence: "%"},
+				{Kind: IssueRawReserved, Component: "query", Offset: 34, Sequence: "|"},
+				{Kind: IssueRawReserved, Component: "query", Offset: 36, Sequence: " "},
+				{Kind: IssueRawReserved, Component: "query", Offset: 44, Sequence: "☕"},
+			},
+		},
+		{
+			name: "plus on path is literal",
+			url:  "https://www.tokopedia.com/c++/books%20new",
+			want: []EncodingIssue{},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ub, err := NewBuilder(Option{URL: tt.url})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			got := ub.EncodingReport()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fail test EncodingReport() got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_RepairEncoding(t *testing.T) {
+	type args struct {
+		name string
+		url  string
+		opt  RepairOptions
+		want string
+	}
+
+	testCases := []args{
+		{
+			name: "decode double encoded",
+			url:  "https://www.tokopedia.com/kopi%2520susu?q=p%2526g&r=%25252F#a%2520b",
+			opt:  RepairOptions{DecodeDoubleEncoded: true},
+			want: "https://www.tokopedia.com/kopi%20susu?q=p%26g&r=%2F#a%20b",
+		},
+		{
+			name: "follow first query space encoding",
+			url:  "https://www.tokopedia.com/search?q=macbook+air%20m2&disc=100%&tag=a|b c&kopi=☕",
+			want: "https://www.tokopedia.com/search?q=macbook+air+m2&disc=100%25&tag=a%7Cb+c&kopi=%E2%98%95",
+		},
+		{
+			name: "space encoding option",
+			url:  "https://www.tokopedia.com/search?q=macbook+air%20m2",
+			opt:  RepairOptions{SpaceEnc: PercentTwentyEncoding},
+			want: "https://www.tokopedia.com/search?q=macbook%20air%20m2",
+		},
+		{
+			name: "keep double encoded nested url",
+			url:  "https://www.tokopedia.com/login?next=%2Fsearch%3Fq%3Dkopi%2520susu&x=a b",
+			want: "https://www.tokopedia.com/login?next=%2Fsearch%3Fq%3Dkopi%2520susu&x=a%20b",
+		},
+		{
+			name: "keep encoded literal percent",
+			url:  "https://www.tokopedia.com/search?d=100%25AB&q=%2520",
+			want: "https://www.tokopedia.com/search?d=100%25AB&q=%2520",
+		},
+		{
+			name: "invalid escape not mistaken as double encoded on second repair",
+			url:  "https://www.tokopedia.com/search?disc=%4g&x=%",
+			want: "https://www.tokopedia.com/search?disc=%254g&x=%25",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ub, err := NewBuilder(Option{URL: tt.url})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			ub.RepairEncoding(tt.opt)
+			got := ub.GetURLResult()
+			if got != tt.want {
+				t.Errorf("fail test RepairEncoding() got %v want %v", got, tt.want)
+			}
+			for _, issue := range ub.EncodingReport() {
+				if tt.opt.DecodeDoubleEncoded || issue.Kind != IssueDoubleEncoded {
+					t.Errorf("fail test RepairEncoding() remaining issue %v", issue)
+				}
+			}
+			// idempotent
+			if repaired := ub.RepairEncoding(tt.opt); len(repaired) > 0 || ub.GetURLResult() != got {
+				t.Errorf("fail test RepairEncoding() twice got %v want %v", ub.GetURLResult(), got)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package uruki
+
+import "testing"
+
+func Test_EncoderEscape(t *testing.T) {
+	type args struct {
+		name      string
+		encoder   Encoder
+		raw       string
+		component URLComponent
+		want      string
+	}
+
+	testCases := []args{
+		{
+			name:      "legacy query value",
+			encoder:   Encoder{Set: EncodeLegacy, SpaceEnc: PercentTwentyEncoding},
+			raw:       "p&g / 100%",
+			component: ComponentQueryValue,
+			want:      "p%26g%20%2F%20100%25",
+		},
+		{
+			name:      "rfc3986 query value keep slash question mark and equal",
+			encoder:   Encoder{Set: EncodeRFC3986},
+			raw:       "a=b/c?d&e+f g#h",
+			component: ComponentQueryValue,
+			want:      "a=b/c?d%26e%2Bf%20g%23h",
+		},
+		{
+			name:      "rfc3986 query key escape equal",
+			encoder:   Encoder{Set: EncodeRFC3986, SpaceEnc: PlusEncoding},
+			raw:       "filter[a=b] c",
+			component: ComponentQueryKey,
+			want:      "filter%5Ba%3Db%5D+c",
+		},
+		{
+			name:      "rfc3986 fragment keep slash and question mark",
+			encoder:   Encoder{Set: EncodeRFC3986},
+			raw:       "/product/123?tab=review#x",
+			component: ComponentFragment,
+			want:      "/product/123?tab=review%23x",
+		},
+		{
+			name:      "rfc3986 path segment escape slash",
+			encoder:   Encoder{Set: EncodeRFC3986},
+			raw:       "a/b:c@d e",
+			component: ComponentPathSegment,
+			want:      "a%2Fb:c@d%20e",
+		},
+		{
+			name:      "rfc3986 userinfo escape colon",
+			encoder:   Encoder{Set: EncodeRFC3986},
+			raw:       "user:p@ss",
+			component: ComponentUserinfo,
+			want:      "user%3Ap%40ss",
+		},
+		{
+			name:      "whatwg fragment only escape control space quote angle backtick",
+			encoder:   Encoder{Set: EncodeWHATWG},
+			raw:       "/a?b=c d\"<>`{}",
+			component: ComponentFragment,
+			want:      "/a?b=c%20d%22%3C%3E%60{}",
+		},
+		{
+			name:      "whatwg path segment",
+			encoder:   Encoder{Set: EncodeWHATWG},
+			raw:       "a/b?c#d{e}^f|g",
+			component: ComponentPathSegment,
+			want:      "a%2Fb%3Fc%23d%7Be%7D%5Ef|g",
+		},
+		{
+			name:      "whatwg userinfo",
+			encoder:   Encoder{Set: EncodeWHATWG},
+			raw:       "us:er@x|y",
+			component: ComponentUserinfo,
+			want:      "us%3Aer%40x%7Cy",
+		},
+		{
+			name:      "whatwg query form urlencoded",
+			encoder:   Encoder{Set: EncodeWHATWG},
+			raw:       "macbook air~m2*",
+			component: ComponentQueryValue,
+			want:      "macbook+air%7Em2*",
+		},
+		{
+			name:      "non ascii escaped as utf-8 bytes",
+			encoder:   Encoder{Set: EncodeRFC3986},
+			raw:       "kopi ☕",
+			component: ComponentFragment,
+			want:      "kopi%20%E2%98%95",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.encoder.Escape(tt.raw, tt.component)
+			if got != tt.want {
+				t.Errorf("fail test Encoder.Escape() got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_BuilderEncodeSet(t *testing.T) {
+	ub, err := NewBuilder(Option{
+		URL:       "https://m.tokopedia.com/discovery",
+		EncodeSet: EncodeRFC3986,
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	ub.SetFragment(SetFragmentOpt{Fragment: "/promo/flash sale?tab=1"})
+	if err := ub.AddQueryParam(AddQueryParamOpt{Key: "next", Val: "/cart?from=promo"}); err != nil {
+		t.Error(err)
+		return
+	}
+	// per call set override builder set
+	if err := ub.AddQueryParam(AddQueryParamOpt{Key: "q", Val: "p&g/x", SpaceEnc: PlusEncoding, EncodeSet: EncodeLegacy}); err != nil {
+		t.Error(err)
+		return
+	}
+	ub.SetPathSegments(SetPathSegmentsOpt{Segments: []string{"discovery", "a/b c"}})
+	want := "https://m.tokopedia.com/discovery/a%2Fb%20c?next=/cart?from=promo&q=p%26g%2Fx#/promo/flash%20sale?tab=1"
+	if got := ub.GetURLResult(); got != want {
+		t.Errorf("fail test EncodeSet got %v want %v", got, want)
+	}
+	if got := ub.GetValueQuery("next"); got != "/cart?from=promo" {
+		t.Errorf("fail test EncodeSet GetValueQuery() got %v want %v", got, "/cart?from=promo")
+	}
+
+	ub.SetFragment(SetFragmentOpt{Fragment: "top 10", SpaceEnc: PlusEncoding, EncodeSet: EncodeWHATWG})
+	want = "https://m.tokopedia.com/discovery/a%2Fb%20c?next=/cart?from=promo&q=p%26g%2Fx#top+10"
+	if got := ub.GetURLResult(); got != want {
+		t.Errorf("fail test EncodeSet got %v want %v", got, want)
+	}
+}
@@ -0,0 +1,74 @@
+package uruki
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Error error of uruki operation with url component and input that failed.
+// cause can be checked with errors.Is against Error sentinels, example errors.Is(err, ErrorInvalidSchemeURI)
+type Error struct {
+	// Op: operation failed, e.g. "NewBuilder", "SetURL" or "AddQueryParam"
+	Op string
+	// Component: url component failed, e.g. "url", "scheme" or "query"
+	Component string
+	// Input: input of component failed, e.g. scheme, query key or raw url
+	Input string
+	// Err: cause of error
+	Err error
+}
+
+// Error message of error, example `uruki: SetURL scheme "http": invalid scheme url not in restricted schemes [https tokopedia]`
+func (e *Error) Error() string {
+	msg := "uruki: " + e.Op
+	if len(e.Component) > 0 {
+		msg += " " + e.Component
+	}
+	if len(e.Input) > 0 {
+		msg += " " + strconv.Quote(e.Input)
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+// Unwrap cause of error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// withOp set operation of Error returned by internal function, other error wrapped as Error of url
+func withOp(op, input string, err error) error {
+	if e, ok := err.(*Error); ok {
+		if len(e.Op) < 1 {
+			e.Op = op
+		}
+		return e
+	}
+	return &Error{Op: op, Component: "url", Input: input, Err: err}
+}
+
+// checkScheme check scheme is in restricted scheme if using restricted scheme
+func (ub *Builder) checkScheme(scheme string) error {
+	if len(ub.restrictedScheme) < 1 || ub.restrictedScheme[scheme] {
+		return nil
+	}
+	schemes := make([]string, 0, len(ub.restrictedScheme))
+	for k := range ub.restrictedScheme {
+		schemes = append(schemes, k)
+	}
+	sort.Strings(schemes)
+	return &Error{Component: "scheme", Input: scheme, Err: fmt.Errorf("%w %v", ErrorInvalidSchemeURI, schemes)}
+}
+
+// checkQueryKey check trimmed query key not empty and not contains space
+func checkQueryKey(op, key string) error {
+	if len(key) < 1 {
+		return &Error{Op: op, Component: "query", Input: key, Err: ErrorKeyEmpty}
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] == ' ' {
+			return &Error{Op: op, Component: "query", Input: key, Err: ErrorKeyContainSpace}
+		}
+	}
+	return nil
+}
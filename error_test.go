@@ -0,0 +1,218 @@
+package uruki
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_Error(t *testing.T) {
+	type args struct {
+		name          string
+		fn            func() error
+		wantErr       error
+		wantOp        string
+		wantComponent string
+		wantInput     string
+		wantMsg       string
+	}
+
+	testCases := []args{
+		{
+			name: "restricted scheme on NewBuilder",
+			fn: func() error {
+				_, err := NewBuilder(Option{URL: "http://www.tokopedia.com", RestrictScheme: []string{"tokopedia", "https"}})
+				return err
+			},
+			wantErr:       ErrorInvalidSchemeURI,
+			wantOp:        "NewBuilder",
+			wantComponent: "scheme",
+			wantInput:     "http",
+			wantMsg:       `uruki: NewBuilder scheme "http": invalid scheme url not in restricted schemes [https tokopedia]`,
+		},
+		{
+			name: "restricted scheme on SetURL",
+			fn: func() error {
+				ub, _ := NewBuilder(Option{URL: "https://www.tokopedia.com", RestrictScheme: []string{"https"}})
+				return ub.SetURL("ftp://www.tokopedia.com")
+			},
+			wantErr:       ErrorInvalidSchemeURI,
+			wantOp:        "SetURL",
+			wantComponent: "scheme",
+			wantInput:     "ftp",
+		},
+		{
+			name: "deprecated ErrInvalidURL",
+			fn: func() error {
+				ub, _ := NewBuilder(Option{URL: "https://www.tokopedia.com", RestrictScheme: []string{"https"}})
+				return ub.SetBaseURL("http://www.tokopedia.com")
+			},
+			wantErr:       ErrInvalidURL,
+			wantOp:        "SetBaseURL",
+			wantComponent: "scheme",
+			wantInput:     "http",
+		},
+		{
+			name: "empty query key",
+			fn: func() error {
+				ub, _ := NewBuilder(Option{URL: "https://www.tokopedia.com"})
+				return ub.AddQueryParam(AddQueryParamOpt{Key: " ", Val: "macbook"})
+			},
+			wantErr:       ErrorKeyEmpty,
+			wantOp:        "AddQueryParam",
+			wantComponent: "query",
+		},
+		{
+			name: "malformed query on auto escape",
+			fn: func() error {
+				_, err := NewBuilder(Option{URL: "https://www.tokopedia.com/search?q=100%", UseEscapeAutomateURL: true, MalformedPolicy: MalformedError})
+				return err
+			},
+			wantErr:       ErrorMalformedQuery,
+			wantOp:        "NewBuilder",
+			wantComponent: "query",
+			wantInput:     "q=100%",
+		},
+		{
+			name: "strict validation",
+			fn: func() error {
+				_, err := NewBuilder(Option{URL: "https://www.tokopedia.com/search?q=macbook air", Strict: true})
+				return err
+			},
+			wantErr:       ErrorRFC3986Invalid,
+			wantOp:        "NewBuilder",
+			wantComponent: "query",
+			wantInput:     "https://www.tokopedia.com/search?q=macbook air",
+		},
+		{
+			name: "nested query key not found",
+			fn: func() error {
+				ub, _ := NewBuilder(Option{URL: "https://www.tokopedia.com"})
+				_, err := ub.NestedQuery("redirect")
+				return err
+			},
+			wantErr:       ErrorKeyNotFound,
+			wantOp:        "NestedQuery",
+			wantComponent: "query",
+			wantInput:     "redirect",
+		},
+		{
+			name: "sign without key",
+			fn: func() error {
+				ub, _ := NewBuilder(Option{URL: "https://www.tokopedia.com"})
+				return ub.Sign(SignOptions{KeyID: "k1"})
+			},
+			wantErr:       ErrorSignKeyEmpty,
+			wantOp:        "Sign",
+			wantComponent: "key",
+			wantInput:     "k1",
+		},
+		{
+			name: "sign with unknown algorithm",
+			fn: func() error {
+				ub, _ := NewBuilder(Option{URL: "https://www.tokopedia.com"})
+				return ub.Sign(SignOptions{Key: []byte("secret"), Algorithm: "md5"})
+			},
+			wantErr:       ErrorSignAlgorithm,
+			wantOp:        "Sign",
+			wantComponent: "algorithm",
+			wantInput:     "md5",
+		},
+		{
+			name: "verify invalid signature",
+			fn: func() error {
+				return Verify("https://www.tokopedia.com/?expires=1&signature=x", VerifyOptions{Keys: map[string][]byte{"": []byte("secret")}})
+			},
+			wantErr:       ErrorSignatureInvalid,
+			wantOp:        "Verify",
+			wantComponent: "signature",
+			wantInput:     "x",
+		},
+		{
+			name: "presign without host",
+			fn: func() error {
+				ub, _ := NewBuilder(Option{URL: "/a.txt"})
+				return ub.PresignV4(PresignV4Options{AccessKeyID: "AK", SecretAccessKey: "SK", Region: "us-east-1"})
+			},
+			wantErr:       ErrorPresignHost,
+			wantOp:        "PresignV4",
+			wantComponent: "host",
+			wantInput:     "/a.txt",
+		},
+		{
+			name: "text directive without start",
+			fn: func() error {
+				ub, _ := NewBuilder(Option{URL: "https://www.tokopedia.com"})
+				return ub.SetTextFragment(TextDirective{Prefix: "harga"})
+			},
+			wantErr:       ErrorTextDirectiveStart,
+			wantOp:        "SetTextFragment",
+			wantComponent: "fragment",
+			wantInput:     "harga-,",
+		},
+		{
+			name: "invalid text directive",
+			fn: func() error {
+				ub, _ := NewBuilder(Option{URL: "https://www.tokopedia.com/#:~:text=a,b,c,d,e"})
+				_, err := ub.GetTextFragment()
+				return err
+			},
+			wantErr:       ErrorTextDirectiveInvalid,
+			wantOp:        "GetTextFragment",
+			wantComponent: "fragment",
+			wantInput:     "text=a,b,c,d,e",
+		},
+		{
+			name: "redirect to other host",
+			fn: func() error {
+				ub, _ := NewBuilder(Option{URL: "https://www.tokopedia.com"})
+				_, err := SafeRedirect(ub, "//evil.com/login", RedirectPolicy{})
+				return err
+			},
+			wantErr:       ErrorRedirectHost,
+			wantOp:        "SafeRedirect",
+			wantComponent: "host",
+			wantInput:     "evil.com",
+		},
+		{
+			name: "redirect dangerous scheme",
+			fn: func() error {
+				ub, _ := NewBuilder(Option{URL: "https://www.tokopedia.com"})
+				_, err := SafeRedirect(ub, "javascript:alert(1)", RedirectPolicy{})
+				return err
+			},
+			wantErr:       ErrorRedirectScheme,
+			wantOp:        "SafeRedirect",
+			wantComponent: "scheme",
+			wantInput:     "javascript",
+		},
+		{
+			name: "functional option conflict",
+			fn: func() error {
+				_, err := New(WithSpaceEncoding(PlusEncoding), WithSpaceEncoding(PercentTwentyEncoding))
+				return err
+			},
+			wantErr:       ErrorOptionConflict,
+			wantOp:        "New",
+			wantComponent: "option",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.fn()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("fail test Error() got %v want %v", err, tt.wantErr)
+			}
+			var e *Error
+			if !errors.As(err, &e) {
+				t.Fatalf("fail test Error() got %T want *Error", err)
+			}
+			if e.Op != tt.wantOp || e.Component != tt.wantComponent || e.Input != tt.wantInput {
+				t.Errorf("fail test Error() got %q %q %q want %q %q %q", e.Op, e.Component, e.Input, tt.wantOp, tt.wantComponent, tt.wantInput)
+			}
+			if len(tt.wantMsg) > 0 && err.Error() != tt.wantMsg {
+				t.Errorf("fail test Error() got %v want %v", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
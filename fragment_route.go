@@ -0,0 +1,38 @@
+package uruki
+
+import "net/url"
+
+// FragmentRoute get child builder of fragment as sub url for SPA hash routing, example "#/product/123?tab=review"
+// path and query inside fragment can be edited with the same API. write back the child with SetFragmentRoute
+func (ub *Builder) FragmentRoute() (*Builder, error) {
+	uri, err := url.Parse(ub.url.EscapedFragment())
+	if err != nil {
+		return nil, withOp("FragmentRoute", ub.url.EscapedFragment(), err)
+	}
+	child := ub.derive(uri)
+	child.restrictedScheme = map[string]bool{}
+	return child, nil
+}
+
+// SetFragmentRoute write child builder back as fragment, encoded with fragment allowed characters
+// so '/' and '?' of route kept as is. nil child return ErrorChildNil
+func (ub *Builder) SetFragmentRoute(child *Builder) error {
+	if child == nil || child.url == nil {
+		return &Error{Op: "SetFragmentRoute", Component: "fragment", Err: ErrorChildNil}
+	}
+	return ub.mutate("SetFragmentRoute", []string{child.url.String()}, func() error {
+		rawFragment := escapeFragment(child.url.String())
+		fragment, err := url.PathUnescape(rawFragment)
+		if err != nil {
+			return withOp("SetFragmentRoute", rawFragment, err)
+		}
+		ub.url.Fragment = fragment
+		ub.url.RawFragment = rawFragment
+		return nil
+	})
+}
+
+// escapeFragment percent encode characters outside of RFC 3986 fragment (pchar / "/" / "?"), existing valid escape kept
+func escapeFragment(s string) string {
+	return percentEncode(s, rfc3986Allowed(ComponentFragment), "", true)
+}
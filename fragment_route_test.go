@@ -0,0 +1,71 @@
+package uruki
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_FragmentRoute(t *testing.T) {
+	ub, err := NewBuilder(Option{
+		URL:                "https://m.tokopedia.com/?source=app#/product/123?tab=review&sort=new",
+		DefaultSpaceEncode: PercentTwentyEncoding,
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	route, err := ub.FragmentRoute()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got := route.GetFullPath(); got != "/product/123" {
+		t.Errorf("fail test FragmentRoute() got %v want %v", got, "/product/123")
+	}
+	if got := route.GetValueQuery("tab"); got != "review" {
+		t.Errorf("fail test FragmentRoute() got %v want %v", got, "review")
+	}
+
+	route.SetPath("/product/456/variant a")
+	route.DeleteKeyQuery("sort")
+	if err := route.AddQueryParam(AddQueryParamOpt{Key: "q", Val: "kaos polos", UseDefaultEncode: true}); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := ub.SetFragmentRoute(route); err != nil {
+		t.Error(err)
+		return
+	}
+	want := "https://m.tokopedia.com/?source=app#/product/456/variant%20a?tab=review&q=kaos%20polos"
+	if got := ub.GetURLResult(); got != want {
+		t.Errorf("fail test SetFragmentRoute() got %v want %v", got, want)
+	}
+}
+
+func Test_FragmentRouteEmpty(t *testing.T) {
+	ub, err := NewBuilder(Option{URL: "https://m.tokopedia.com/"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	route, err := ub.FragmentRoute()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	route.SetPath("/home")
+	if err := ub.SetFragmentRoute(route); err != nil {
+		t.Error(err)
+		return
+	}
+	want := "https://m.tokopedia.com/#/home"
+	if got := ub.GetURLResult(); got != want {
+		t.Errorf("fail test SetFragmentRoute() got %v want %v", got, want)
+	}
+	if err := ub.SetFragmentRoute(nil); !errors.Is(err, ErrorChildNil) {
+		t.Errorf("fail test SetFragmentRoute() nil child got %v want %v", err, ErrorChildNil)
+	}
+	if got := ub.GetURLResult(); got != want {
+		t.Errorf("fail test SetFragmentRoute() nil child got %v want %v", got, want)
+	}
+}
@@ -1,3 +1,7 @@
 module github.com/forderation/uruki
 
-go 1.16
+go 1.21
+
+require golang.org/x/net v0.30.0
+
+require golang.org/x/text v0.19.0 // indirect
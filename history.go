@@ -0,0 +1,158 @@
+package uruki
+
+import "strconv"
+
+// HistoryEntry mutation of builder recorded in history
+type HistoryEntry struct {
+	// Op: name of mutation method, e.g. "SetPath" or "AddQueryParam"
+	Op string
+	// Args: arguments of mutation, secret keys never recorded
+	Args []string
+	// Before: url before mutation
+	Before URL
+	// After: url after mutation
+	After URL
+}
+
+// SnapshotID id of url snapshot taken by Snapshot
+type SnapshotID int
+
+// history bounded mutation history, entries[:cursor] applied and entries[cursor:] can be redone
+type history struct {
+	size    int
+	entries []HistoryEntry
+	cursor  int
+}
+
+// newHistory create history keeping at most size entries, nil when size is not positive
+func newHistory(size int) *history {
+	if size < 1 {
+		return nil
+	}
+	return &history{size: size, entries: make([]HistoryEntry, 0)}
+}
+
+// copy deep copy of history, entries are immutable so only slice copied
+func (h *history) copy() *history {
+	if h == nil {
+		return nil
+	}
+	entries := make([]HistoryEntry, len(h.entries))
+	copy(entries, h.entries)
+	return &history{size: h.size, entries: entries, cursor: h.cursor}
+}
+
+// record add applied entry, entries can be redone are dropped and the oldest entry removed when full
+func (h *history) record(entry HistoryEntry) {
+	h.entries = append(h.entries[:h.cursor], entry)
+	if len(h.entries) > h.size {
+		h.entries = h.entries[len(h.entries)-h.size:]
+	}
+	h.cursor = len(h.entries)
+}
+
+// mutate run mutation fn, when url changed notify hooks and record it into history. change vetoed by hook reverted.
+// mutation called by other mutation notified and recorded once
+func (ub *Builder) mutate(op string, args []string, fn func() error) error {
+	if ub.mutating {
+		return fn()
+	}
+	ub.mutating = true
+	defer func() {
+		ub.mutating = false
+	}()
+	if ub.history == nil && len(ub.hooks) < 1 {
+		return fn()
+	}
+	before := ub.URL()
+	if err := fn(); err != nil {
+		return err
+	}
+	after := ub.URL()
+	if before.String() == after.String() {
+		return nil
+	}
+	event := ChangeEvent{Op: op, Args: args, Before: before, After: after}
+	if err := ub.beforeChange(event); err != nil {
+		ub.url = copyURL(&before.uri)
+		return err
+	}
+	ub.afterChange(event)
+	if ub.history != nil {
+		ub.history.record(HistoryEntry{Op: op, Args: args, Before: before, After: after})
+	}
+	return nil
+}
+
+// History applied mutations from the oldest, empty when history disabled. see Option HistorySize
+func (ub *Builder) History() []HistoryEntry {
+	if ub.history == nil {
+		return []HistoryEntry{}
+	}
+	entries := make([]HistoryEntry, ub.history.cursor)
+	copy(entries, ub.history.entries)
+	return entries
+}
+
+// Undo revert the last applied mutation, return ErrorHistoryDisabled or ErrorNothingToUndo
+func (ub *Builder) Undo() error {
+	if ub.history == nil {
+		return ErrorHistoryDisabled
+	}
+	if ub.history.cursor < 1 {
+		return ErrorNothingToUndo
+	}
+	if err := ub.moveHistory("Undo", ub.history.entries[ub.history.cursor-1].Before); err != nil {
+		return err
+	}
+	ub.history.cursor--
+	return nil
+}
+
+// Redo apply again the last undone mutation, return ErrorHistoryDisabled or ErrorNothingToRedo
+func (ub *Builder) Redo() error {
+	if ub.history == nil {
+		return ErrorHistoryDisabled
+	}
+	if ub.history.cursor >= len(ub.history.entries) {
+		return ErrorNothingToRedo
+	}
+	if err := ub.moveHistory("Redo", ub.history.entries[ub.history.cursor].After); err != nil {
+		return err
+	}
+	ub.history.cursor++
+	return nil
+}
+
+// moveHistory replace url with url of history, hooks notified but the change not recorded
+func (ub *Builder) moveHistory(op string, target URL) error {
+	event := ChangeEvent{Op: op, Before: ub.URL(), After: target}
+	if err := ub.beforeChange(event); err != nil {
+		return err
+	}
+	ub.url = copyURL(&target.uri)
+	ub.afterChange(event)
+	return nil
+}
+
+// Snapshot save current url and return its id for Restore, snapshot available without history
+func (ub *Builder) Snapshot() SnapshotID {
+	if ub.snapshots == nil {
+		ub.snapshots = map[SnapshotID]URL{}
+	}
+	ub.lastSnapshot++
+	ub.snapshots[ub.lastSnapshot] = ub.URL()
+	return ub.lastSnapshot
+}
+
+// Restore replace url with snapshot of id, recorded in history so it can be undone. return ErrorSnapshotNotFound
+func (ub *Builder) Restore(id SnapshotID) error {
+	snapshot, ok := ub.snapshots[id]
+	if !ok {
+		return &Error{Op: "Restore", Component: "snapshot", Input: strconv.Itoa(int(id)), Err: ErrorSnapshotNotFound}
+	}
+	return ub.mutate("Restore", []string{strconv.Itoa(int(id))}, func() error {
+		ub.url = copyURL(&snapshot.uri)
+		return nil
+	})
+}
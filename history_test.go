@@ -0,0 +1,139 @@
+package uruki
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_History(t *testing.T) {
+	ub, err := NewBuilder(Option{URL: "https://www.tokopedia.com/search?q=macbook", HistorySize: 3})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	ub.SetPath("/discovery")
+	_ = ub.AddQueryParam(AddQueryParamOpt{Key: "page", Val: "2"})
+	ub.SetFragment(SetFragmentOpt{Fragment: "review"})
+
+	history := ub.History()
+	if len(history) != 3 {
+		t.Fatalf("fail test History() got %v want %v", len(history), 3)
+	}
+	entry := history[1]
+	if entry.Op != "AddQueryParam" || len(entry.Args) != 2 || entry.Args[0] != "page" ||
+		entry.Before.String() != "https://www.tokopedia.com/discovery?q=macbook" ||
+		entry.After.String() != "https://www.tokopedia.com/discovery?q=macbook&page=2" {
+		t.Errorf("fail test History() got %+v", entry)
+	}
+
+	type args struct {
+		name    string
+		fn      func() error
+		want    string
+		wantErr error
+	}
+	testCases := []args{
+		{name: "undo fragment", fn: ub.Undo, want: "https://www.tokopedia.com/discovery?q=macbook&page=2"},
+		{name: "undo query", fn: ub.Undo, want: "https://www.tokopedia.com/discovery?q=macbook"},
+		{name: "redo query", fn: ub.Redo, want: "https://www.tokopedia.com/discovery?q=macbook&page=2"},
+		{name: "undo query again", fn: ub.Undo, want: "https://www.tokopedia.com/discovery?q=macbook"},
+		{name: "undo path", fn: ub.Undo, want: "https://www.tokopedia.com/search?q=macbook"},
+		{name: "nothing to undo", fn: ub.Undo, want: "https://www.tokopedia.com/search?q=macbook", wantErr: ErrorNothingToUndo},
+		{name: "redo path", fn: ub.Redo, want: "https://www.tokopedia.com/discovery?q=macbook"},
+		{
+			name: "new mutation drop redo",
+			fn: func() error {
+				ub.DeleteKeyQuery("q")
+				return ub.Redo()
+			},
+			want:    "https://www.tokopedia.com/discovery",
+			wantErr: ErrorNothingToRedo,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.fn()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("fail error test History() got %v want %v", err, tt.wantErr)
+			}
+			if got := ub.GetURLResult(); got != tt.want {
+				t.Errorf("fail test History() got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_HistoryBounded(t *testing.T) {
+	ub, err := New(WithURL("https://www.tokopedia.com"), WithHistory(2))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	ub.SetPath("/a")
+	ub.SetPath("/a")
+	ub.SetPath("/b")
+	ub.SetPath("/c")
+	if got := len(ub.History()); got != 2 {
+		t.Errorf("fail test History() size got %v want %v", got, 2)
+	}
+	_ = ub.Undo()
+	_ = ub.Undo()
+	if err := ub.Undo(); !errors.Is(err, ErrorNothingToUndo) {
+		t.Errorf("fail error test Undo() got %v want %v", err, ErrorNothingToUndo)
+	}
+	if got := ub.GetFullPath(); got != "/a" {
+		t.Errorf("fail test Undo() got %v want %v", got, "/a")
+	}
+
+	// Sign change query with nested DeleteKeyQuery, recorded once
+	_ = ub.Sign(SignOptions{Key: []byte("secret")})
+	_ = ub.Sign(SignOptions{Key: []byte("secret")})
+	if got := ub.History(); len(got) != 1 || got[0].Op != "Sign" {
+		t.Errorf("fail test History() nested mutation got %+v", got)
+	}
+
+	plain, _ := NewBuilder(Option{URL: "https://www.tokopedia.com"})
+	plain.SetPath("/a")
+	if err := plain.Undo(); !errors.Is(err, ErrorHistoryDisabled) {
+		t.Errorf("fail error test Undo() got %v want %v", err, ErrorHistoryDisabled)
+	}
+}
+
+func Test_SnapshotRestore(t *testing.T) {
+	ub, err := NewBuilder(Option{URL: "https://www.tokopedia.com/cart", HistorySize: 10})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	id := ub.Snapshot()
+	ub.SetPath("/checkout")
+	_ = ub.AddQueryParam(AddQueryParamOpt{Key: "promo", Val: "flash"})
+
+	clone := ub.Clone()
+	if err := ub.Restore(id); err != nil {
+		t.Error(err)
+	}
+	if got := ub.GetURLResult(); got != "https://www.tokopedia.com/cart" {
+		t.Errorf("fail test Restore() got %v want %v", got, "https://www.tokopedia.com/cart")
+	}
+	if err := ub.Undo(); err != nil {
+		t.Error(err)
+	}
+	if got := ub.GetURLResult(); got != "https://www.tokopedia.com/checkout?promo=flash" {
+		t.Errorf("fail test Undo() Restore got %v want %v", got, "https://www.tokopedia.com/checkout?promo=flash")
+	}
+	if err := ub.Restore(SnapshotID(99)); !errors.Is(err, ErrorSnapshotNotFound) {
+		t.Errorf("fail error test Restore() got %v want %v", err, ErrorSnapshotNotFound)
+	}
+
+	// clone keep its own history and snapshots
+	if err := clone.Restore(id); err != nil {
+		t.Error(err)
+	}
+	if got := len(clone.History()); got != 3 {
+		t.Errorf("fail test Clone() history got %v want %v", got, 3)
+	}
+	if got := len(ub.History()); got != 2 {
+		t.Errorf("fail test Clone() history changed builder got %v want %v", got, 2)
+	}
+}
@@ -0,0 +1,64 @@
+package uruki
+
+import "fmt"
+
+// ChangeEvent change of builder url passed to Hook
+type ChangeEvent struct {
+	// Op: name of mutation method, e.g. "SetPath", "AddQueryParam", "Undo" or "Redo"
+	Op string
+	// Args: arguments of mutation, secret keys never passed
+	Args []string
+	// Before: url before change
+	Before URL
+	// After: url after change
+	After URL
+}
+
+// Hook observe changes of builder url, register with Option Hooks. BeforeChange returning error veto the change
+// and url kept as before, methods without error return such as SetPath silently keep the url.
+// AfterChange called after change applied. child builders of NestedQuery, FragmentRoute and SafeRedirect created without hooks,
+// hooks notified when child written back into the builder
+type Hook interface {
+	BeforeChange(event ChangeEvent) error
+	AfterChange(event ChangeEvent)
+}
+
+// HookFuncs Hook from functions, nil function skipped
+type HookFuncs struct {
+	// Before: called before change applied, return error to veto the change
+	Before func(event ChangeEvent) error
+	// After: called after change applied
+	After func(event ChangeEvent)
+}
+
+// BeforeChange see Hook
+func (h HookFuncs) BeforeChange(event ChangeEvent) error {
+	if h.Before == nil {
+		return nil
+	}
+	return h.Before(event)
+}
+
+// AfterChange see Hook
+func (h HookFuncs) AfterChange(event ChangeEvent) {
+	if h.After != nil {
+		h.After(event)
+	}
+}
+
+// beforeChange call BeforeChange of hooks in order, stop at the first veto
+func (ub *Builder) beforeChange(event ChangeEvent) error {
+	for _, hook := range ub.hooks {
+		if err := hook.BeforeChange(event); err != nil {
+			return &Error{Op: event.Op, Component: "hook", Err: fmt.Errorf("%w: %w", ErrorChangeVetoed, err)}
+		}
+	}
+	return nil
+}
+
+// afterChange call AfterChange of hooks in order
+func (ub *Builder) afterChange(event ChangeEvent) {
+	for _, hook := range ub.hooks {
+		hook.AfterChange(event)
+	}
+}
@@ -0,0 +1,216 @@
+package uruki
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var errorRemoveWarehouse = errors.New("whid must not be removed")
+
+// keepWarehouseHook veto change removing whid query and log host changes
+type keepWarehouseHook struct {
+	hostChanges []string
+}
+
+func (h *keepWarehouseHook) BeforeChange(event ChangeEvent) error {
+	if len(event.Before.Query("whid")) > 0 && len(event.After.Query("whid")) < 1 {
+		return errorRemoveWarehouse
+	}
+	return nil
+}
+
+func (h *keepWarehouseHook) AfterChange(event ChangeEvent) {
+	if event.Before.Host() != event.After.Host() {
+		h.hostChanges = append(h.hostChanges, event.Op+" "+event.Before.Host()+" "+event.After.Host())
+	}
+}
+
+func Test_Hook(t *testing.T) {
+	type args struct {
+		name    string
+		fn      func(ub *Builder) error
+		want    string
+		wantErr error
+	}
+
+	rawURL := "https://www.tokopedia.com/cart?whid=13355454&src=search"
+	testCases := []args{
+		{
+			name: "veto delete key query",
+			fn: func(ub *Builder) error {
+				ub.DeleteKeyQuery("whid")
+				return nil
+			},
+			want: rawURL,
+		},
+		{
+			name: "veto set url",
+			fn: func(ub *Builder) error {
+				return ub.SetURL("https://www.tokopedia.com/cart?src=search")
+			},
+			want:    rawURL,
+			wantErr: errorRemoveWarehouse,
+		},
+		{
+			name: "allow delete other key",
+			fn: func(ub *Builder) error {
+				ub.DeleteKeyQuery("src")
+				return nil
+			},
+			want: "https://www.tokopedia.com/cart?whid=13355454",
+		},
+		{
+			name: "allow add query",
+			fn: func(ub *Builder) error {
+				return ub.AddQueryParam(AddQueryParamOpt{Key: "page", Val: "2"})
+			},
+			want: rawURL + "&page=2",
+		},
+		{
+			name: "veto strip tracking params",
+			fn: func(ub *Builder) error {
+				if stripped := ub.StripTrackingParams(StripProfile{Name: "warehouse", Keys: []string{"whid"}}); len(stripped) > 0 {
+					return fmt.Errorf("vetoed strip report removed %v", stripped)
+				}
+				return nil
+			},
+			want: rawURL,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ub, err := NewBuilder(Option{URL: rawURL, Hooks: []Hook{&keepWarehouseHook{}}})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			err = tt.fn(ub)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("fail error test Hook() got %v want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && !errors.Is(err, ErrorChangeVetoed) {
+				t.Errorf("fail error test Hook() got %v want %v", err, ErrorChangeVetoed)
+			}
+			if got := ub.GetURLResult(); got != tt.want {
+				t.Errorf("fail test Hook() got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_HookAfterChange(t *testing.T) {
+	hook := &keepWarehouseHook{}
+	events := make([]string, 0)
+	ub, err := New(
+		WithURL("https://www.tokopedia.com/cart"),
+		WithHistory(10),
+		WithHooks(hook, HookFuncs{After: func(event ChangeEvent) {
+			events = append(events, event.Op)
+		}}),
+	)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := ub.SetBaseURL("https://m.tokopedia.com"); err != nil {
+		t.Error(err)
+	}
+	ub.SetPath("/cart")
+	ub.SetFragment(SetFragmentOpt{Fragment: "promo"})
+	ub.DeleteFragment()
+	if err := ub.Undo(); err != nil {
+		t.Error(err)
+	}
+
+	wantEvents := []string{"SetBaseURL", "SetFragment", "DeleteFragment", "Undo"}
+	if len(events) != len(wantEvents) {
+		t.Fatalf("fail test AfterChange() got %v want %v", events, wantEvents)
+	}
+	for i := range wantEvents {
+		if events[i] != wantEvents[i] {
+			t.Errorf("fail test AfterChange() got %v want %v", events, wantEvents)
+		}
+	}
+	if len(hook.hostChanges) != 1 || hook.hostChanges[0] != "SetBaseURL www.tokopedia.com m.tokopedia.com" {
+		t.Errorf("fail test AfterChange() host changes got %v", hook.hostChanges)
+	}
+
+	// veto of undo keep history position
+	vetoed, _ := NewBuilder(Option{URL: "https://www.tokopedia.com/cart", HistorySize: 10, Hooks: []Hook{HookFuncs{
+		Before: func(event ChangeEvent) error {
+			if event.Op == "Undo" {
+				return errorRemoveWarehouse
+			}
+			return nil
+		},
+	}}})
+	vetoed.SetPath("/checkout")
+	if err := vetoed.Undo(); !errors.Is(err, ErrorChangeVetoed) {
+		t.Errorf("fail error test Undo() got %v want %v", err, ErrorChangeVetoed)
+	}
+	if got := len(vetoed.History()); got != 1 || vetoed.GetFullPath() != "/checkout" {
+		t.Errorf("fail test Undo() vetoed got %v %v", got, vetoed.GetFullPath())
+	}
+
+	// vetoed repair report no repaired issue
+	vetoed, _ = NewBuilder(Option{URL: "https://www.tokopedia.com/kopi%2520susu?disc=100%", Hooks: []Hook{HookFuncs{
+		Before: func(event ChangeEvent) error {
+			return errorRemoveWarehouse
+		},
+	}}})
+	if issues := vetoed.RepairEncoding(RepairOptions{}); len(issues) > 0 || vetoed.GetURLResult() != "https://www.tokopedia.com/kopi%2520susu?disc=100%" {
+		t.Errorf("fail test RepairEncoding() vetoed got %v %v", issues, vetoed.GetURLResult())
+	}
+}
+
+func Test_HookDerivedChild(t *testing.T) {
+	hook := &keepWarehouseHook{}
+	ops := make([]string, 0)
+	ub, err := NewBuilder(Option{
+		URL: "https://www.tokopedia.com/cart?whid=13355454&extParam=whid%3D1%26src%3Dsearch#/product?whid=2",
+		Hooks: []Hook{hook, HookFuncs{After: func(event ChangeEvent) {
+			ops = append(ops, event.Op)
+		}}},
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// child without hooks, whid of nested query and fragment can be removed
+	ext, err := ub.NestedQuery("extParam")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	ext.DeleteKeyQuery("whid")
+	route, err := ub.FragmentRoute()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	route.DeleteKeyQuery("whid")
+	next, err := SafeRedirect(ub, "/checkout", RedirectPolicy{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	next.SetPath("/home")
+	if got := ext.GetValueQuery("whid") + route.GetValueQuery("whid"); len(got) > 0 || next.GetFullPath() != "/home" || len(ops) > 0 {
+		t.Errorf("fail test derived child hooks got %v %v %v", got, next.GetFullPath(), ops)
+	}
+
+	// hooks notified with url of the builder when child written back
+	if err := ub.SetNestedQuery("extParam", ext); err != nil {
+		t.Error(err)
+	}
+	if err := ub.SetFragmentRoute(route); err != nil {
+		t.Error(err)
+	}
+	want := "https://www.tokopedia.com/cart?whid=13355454&extParam=src%3Dsearch#/product"
+	if got := ub.GetURLResult(); got != want || len(ops) != 2 {
+		t.Errorf("fail test derived child write back got %v %v want %v", got, ops, want)
+	}
+}
@@ -15,19 +15,54 @@ func (ub *Builder) setURL(rawURL string) error {
 	return nil
 }
 
+// replaceURL parse and replace url, existing query escaped when UseEscapeAutomateURL. old url kept on error
+func (ub *Builder) replaceURL(rawURL string) error {
+	prevURL := ub.url
+	if err := ub.setURL(rawURL); err != nil {
+		return err
+	}
+	if ub.useEscapeAutomateURL {
+		if err := ub.queryEscapeAutomate(); err != nil {
+			ub.url = prevURL
+			return err
+		}
+	}
+	return nil
+}
+
 // parseURL parsing given uri and check if scheme in restricted if using restricted scheme
 func (ub *Builder) parseURL(rawURL string) (*url.URL, error) {
-	uri, err := url.Parse(rawURL)
+	uri, err := ub.parseURLMode(rawURL)
 	if err != nil {
 		return nil, err
 	}
 	// check it's an acceptable scheme
-	if len(ub.restrictedScheme) > 0 && !ub.restrictedScheme[uri.Scheme] {
-		return nil, ErrorInvalidSchemeURI
+	if err := ub.checkScheme(uri.Scheme); err != nil {
+		return nil, err
 	}
 	return uri, nil
 }
 
+// parseURLMode parsing given uri with parser of parse mode, validated first on strict mode
+func (ub *Builder) parseURLMode(rawURL string) (*url.URL, error) {
+	if ub.strict || ub.parseMode == RFC3986Strict {
+		if errs := Validate(rawURL); len(errs) > 0 {
+			return nil, &Error{Component: errs[0].Component, Input: rawURL, Err: errs[0]}
+		}
+	}
+	if ub.parseMode == WHATWG {
+		if len(rawURL) < 1 {
+			return &url.URL{}, nil
+		}
+		uri, err := ParseWHATWG(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		return uri.netURL(), nil
+	}
+	return url.Parse(rawURL)
+}
+
 // setRestrictedScheme lookup restricted scheme save into map
 func (ub *Builder) setRestrictedScheme(schemes []string) {
 	mapScheme := make(map[string]bool)
@@ -38,39 +73,133 @@ func (ub *Builder) setRestrictedScheme(schemes []string) {
 	ub.restrictedScheme = mapScheme
 }
 
-// queryEscapeAutomate escape all query parameter from existing url
-func (ub *Builder) queryEscapeAutomate() {
+// derive create new builder from given url with the same options as current builder, history of builder not copied.
+// hooks not copied since derived url is not url of the builder, e.g. nested query or fragment route
+func (ub *Builder) derive(uri *url.URL) *Builder {
+	derived := &Builder{
+		url:                  uri,
+		defaultSpaceEncode:   ub.defaultSpaceEncode,
+		restrictedScheme:     ub.restrictedScheme,
+		useEscapeAutomateURL: ub.useEscapeAutomateURL,
+		encodeSet:            ub.encodeSet,
+		parseMode:            ub.parseMode,
+		strict:               ub.strict,
+		malformedPolicy:      ub.malformedPolicy,
+		jsonFormat:           ub.jsonFormat,
+	}
+	if ub.history != nil {
+		derived.history = newHistory(ub.history.size)
+	}
+	return derived
+}
+
+// copyURL deep copy of url, userinfo not shared
+func copyURL(uri *url.URL) *url.URL {
+	c := *uri
+	if uri.User != nil {
+		if password, ok := uri.User.Password(); ok {
+			c.User = url.UserPassword(uri.User.Username(), password)
+		} else {
+			c.User = url.User(uri.User.Username())
+		}
+	}
+	return &c
+}
+
+// queryPair key and value of query parameter
+type queryPair struct {
+	key string
+	val string
+}
+
+// escapeQuery query escape value and replace space encoding with spaceEnc
+func escapeQuery(val, spaceEnc string) string {
+	return strings.ReplaceAll(url.QueryEscape(val), PlusEncoding, spaceEnc)
+}
+
+// parseRawQueryPairs split raw query into decoded pairs keeping order, invalid escape kept as is
+func parseRawQueryPairs(rawQuery string) []queryPair {
+	pairs := make([]queryPair, 0)
+	if len(rawQuery) < 1 {
+		return pairs
+	}
+	for _, queryParam := range strings.Split(rawQuery, ampersandStr) {
+		if len(queryParam) < 1 {
+			continue
+		}
+		q, v, _ := strings.Cut(queryParam, "=")
+		key, err := url.QueryUnescape(q)
+		if err != nil {
+			key = q
+		}
+		val, err := url.QueryUnescape(v)
+		if err != nil {
+			val = v
+		}
+		pairs = append(pairs, queryPair{key, val})
+	}
+	return pairs
+}
+
+// lookupQueryPair get first value of key
+func lookupQueryPair(pairs []queryPair, key string) (string, bool) {
+	for _, v := range pairs {
+		if v.key == key {
+			return v.val, true
+		}
+	}
+	return "", false
+}
+
+// appendRawQuery append already escaped query parameter into raw query
+func (ub *Builder) appendRawQuery(queryParam string) {
 	rawQuery := ub.url.RawQuery
 	if len(rawQuery) > 0 {
-		keyVal := strings.Split(rawQuery, ampersandStr)
-		buildRawResult := make([]string, 0)
-		for _, queryParam := range keyVal {
-			qv := strings.Split(queryParam, "=")
-			if len(qv) > 0 {
-				q := qv[0]
-				v := ""
-				if len(qv) > 1 {
-					v = qv[1]
-				}
-				qParsed, err := url.QueryUnescape(q)
-				if err != nil {
-					qParsed = v
-				} else {
-					qParsed = url.QueryEscape(qParsed)
-					qParsed = strings.ReplaceAll(qParsed, PlusEncoding, ub.defaultSpaceEncode)
-				}
-				vParsed, err := url.QueryUnescape(v)
-				if err != nil {
-					vParsed = v
-				} else {
-					vParsed = url.QueryEscape(vParsed)
-					vParsed = strings.ReplaceAll(vParsed, PlusEncoding, ub.defaultSpaceEncode)
-				}
-				buildRawResult = append(buildRawResult, qParsed+"="+vParsed)
+		rawQuery += ampersandStr
+	}
+	ub.url.RawQuery = rawQuery + queryParam
+}
+
+// setRawQueryValue replace value of first existing key with already escaped value, otherwise append it
+func (ub *Builder) setRawQueryValue(key, escapedVal string) {
+	if len(ub.url.RawQuery) > 0 {
+		keyVal := strings.Split(ub.url.RawQuery, ampersandStr)
+		for i, queryParam := range keyVal {
+			q, _, _ := strings.Cut(queryParam, "=")
+			qKey, err := url.QueryUnescape(q)
+			if err != nil {
+				qKey = q
+			}
+			if qKey == key {
+				keyVal[i] = q + "=" + escapedVal
+				ub.url.RawQuery = strings.Join(keyVal, ampersandStr)
+				return
 			}
 		}
-		if len(buildRawResult) > 0 {
-			ub.url.RawQuery = strings.Join(buildRawResult, ampersandStr)
+	}
+	ub.appendRawQuery(url.QueryEscape(key) + "=" + escapedVal)
+}
+
+// deleteRawQueryKeys remove all parameters of keys, other parameters kept as is including value with '=' and key without value
+func (ub *Builder) deleteRawQueryKeys(keys ...string) {
+	if len(ub.url.RawQuery) < 1 {
+		return
+	}
+	deleted := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		deleted[key] = true
+	}
+	keyVal := strings.Split(ub.url.RawQuery, ampersandStr)
+	buildRawResult := make([]string, 0, len(keyVal))
+	for _, queryParam := range keyVal {
+		q, _, _ := strings.Cut(queryParam, "=")
+		qKey, err := url.QueryUnescape(q)
+		if err != nil {
+			qKey = q
+		}
+		if !deleted[qKey] {
+			buildRawResult = append(buildRawResult, queryParam)
 		}
 	}
+	ub.url.RawQuery = strings.Join(buildRawResult, ampersandStr)
 }
@@ -0,0 +1,246 @@
+package uruki
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// JSONFormat format of builder encoded as JSON
+type JSONFormat int
+
+const (
+	// JSONString url encoded as JSON string, default
+	JSONString JSONFormat = iota
+	// JSONComponents url encoded as component object {scheme, host, path, query, fragment}, query kept in order
+	JSONComponents
+	// JSONLosslessComponents url encoded as lossless component object of Components, see ToComponents
+	JSONLosslessComponents
+)
+
+// binaryVersion version of binary encoding, first byte of MarshalBinary
+const binaryVersion byte = 1
+
+// jsonComponents component object of JSONComponents, path, query and fragment decoded
+type jsonComponents struct {
+	Scheme   string      `json:"scheme,omitempty"`
+	Host     string      `json:"host,omitempty"`
+	Path     string      `json:"path,omitempty"`
+	Query    []jsonQuery `json:"query,omitempty"`
+	Fragment string      `json:"fragment,omitempty"`
+}
+
+// jsonQuery query parameter of component object
+type jsonQuery struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// MarshalText implement encoding.TextMarshaler, url as escaped string
+func (ub *Builder) MarshalText() ([]byte, error) {
+	if ub.url == nil {
+		return []byte{}, nil
+	}
+	return []byte(ub.url.String()), nil
+}
+
+// UnmarshalText implement encoding.TextUnmarshaler. options of builder used as decoding context,
+// so url with scheme not in restricted scheme of builder rejected
+func (ub *Builder) UnmarshalText(text []byte) error {
+	return ub.decode("UnmarshalText", string(text))
+}
+
+// MarshalJSON implement json.Marshaler, encoded as string or component object by JSONFormat of builder
+func (ub *Builder) MarshalJSON() ([]byte, error) {
+	if ub.url == nil {
+		return json.Marshal("")
+	}
+	switch ub.jsonFormat {
+	case JSONComponents:
+		return json.Marshal(ub.jsonComponents())
+	case JSONLosslessComponents:
+		return json.Marshal(ub.ToComponents())
+	}
+	return json.Marshal(ub.url.String())
+}
+
+// UnmarshalJSON implement json.Unmarshaler, decode string and both component objects of JSONComponents and
+// JSONLosslessComponents, object with unknown field rejected. null is no-op.
+// options of builder used as decoding context, see UnmarshalText
+func (ub *Builder) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '{' {
+		raw, err := ub.decodeJSONComponents(data)
+		if err != nil {
+			return &Error{Op: "UnmarshalJSON", Component: "json", Err: err}
+		}
+		return ub.decode("UnmarshalJSON", raw)
+	}
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return &Error{Op: "UnmarshalJSON", Component: "json", Err: err}
+	}
+	return ub.decode("UnmarshalJSON", raw)
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler, version byte followed by url
+func (ub *Builder) MarshalBinary() ([]byte, error) {
+	text, err := ub.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{binaryVersion}, text...), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, options of builder used as decoding context
+func (ub *Builder) UnmarshalBinary(data []byte) error {
+	return ub.decodeBinary("UnmarshalBinary", data)
+}
+
+// GobEncode implement gob.GobEncoder, the same as MarshalBinary
+func (ub *Builder) GobEncode() ([]byte, error) {
+	return ub.MarshalBinary()
+}
+
+// GobDecode implement gob.GobDecoder, the same as UnmarshalBinary
+func (ub *Builder) GobDecode(data []byte) error {
+	return ub.decodeBinary("GobDecode", data)
+}
+
+// Value implement driver.Valuer, url stored as string and nil builder as NULL
+func (ub *Builder) Value() (driver.Value, error) {
+	if ub == nil {
+		return nil, nil
+	}
+	text, err := ub.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(text), nil
+}
+
+// Scan implement sql.Scanner for string and []byte column, NULL scanned as empty url.
+// options of builder used as decoding context, see UnmarshalText
+func (ub *Builder) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		return ub.decode("Scan", v)
+	case []byte:
+		return ub.decode("Scan", string(v))
+	case nil:
+		ub.initURL()
+		return ub.mutate("Scan", nil, func() error {
+			ub.url = &url.URL{}
+			return nil
+		})
+	}
+	return &Error{Op: "Scan", Component: "sql", Input: fmt.Sprintf("%T", src), Err: ErrorScanType}
+}
+
+// initURL set empty url of zero value builder, so builder can be decoded without NewBuilder
+func (ub *Builder) initURL() {
+	if ub.url == nil {
+		ub.url = &url.URL{}
+	}
+}
+
+// decode replace url with raw url decoded by op, parsed and checked with builder options
+func (ub *Builder) decode(op, raw string) error {
+	ub.initURL()
+	return ub.mutate(op, []string{raw}, func() error {
+		if err := ub.replaceURL(raw); err != nil {
+			return withOp(op, raw, err)
+		}
+		return nil
+	})
+}
+
+// decodeBinary check version of binary encoding and decode url
+func (ub *Builder) decodeBinary(op string, data []byte) error {
+	if len(data) < 1 || data[0] != binaryVersion {
+		return &Error{Op: op, Component: "binary", Err: ErrorBinaryInvalid}
+	}
+	return ub.decode(op, string(data[1:]))
+}
+
+// jsonComponents component object of url
+func (ub *Builder) jsonComponents() jsonComponents {
+	c := jsonComponents{
+		Scheme:   ub.url.Scheme,
+		Host:     ub.url.Host,
+		Path:     ub.url.Path,
+		Fragment: ub.url.Fragment,
+	}
+	for _, v := range parseRawQueryPairs(ub.url.RawQuery) {
+		c.Query = append(c.Query, jsonQuery{Key: v.key, Value: v.val})
+	}
+	return c
+}
+
+// componentsURL raw url of component object, query escaped with default space encoding of builder
+func (ub *Builder) componentsURL(c jsonComponents) string {
+	spaceEnc := ub.defaultSpaceEncode
+	if len(spaceEnc) < 1 {
+		spaceEnc = PlusEncoding
+	}
+	uri := url.URL{Scheme: c.Scheme, Host: c.Host, Path: c.Path, Fragment: c.Fragment}
+	// path without host and leading slash is opaque, e.g. "mailto:cs@tokopedia.com"
+	uri.OmitHost = len(c.Host) < 1 && !strings.HasPrefix(c.Path, "/")
+	for _, v := range c.Query {
+		if len(uri.RawQuery) > 0 {
+			uri.RawQuery += ampersandStr
+		}
+		uri.RawQuery += escapeQuery(v.Key, spaceEnc) + "=" + escapeQuery(v.Value, spaceEnc)
+	}
+	return uri.String()
+}
+
+// decodeJSONComponents raw url of component object. builder with JSONLosslessComponents format decode object as
+// Components first, so lossless object with fields of JSONComponents only kept lossless, otherwise JSONComponents first.
+// when both failed, error of Components returned only if object has top level field of Components only
+func (ub *Builder) decodeJSONComponents(data []byte) (string, error) {
+	var lossless Components
+	losslessErr := decodeJSONStrict(data, &lossless)
+	if losslessErr == nil && ub.jsonFormat == JSONLosslessComponents {
+		return lossless.String(), nil
+	}
+	var c jsonComponents
+	err := decodeJSONStrict(data, &c)
+	if err == nil {
+		return ub.componentsURL(c), nil
+	}
+	if losslessErr == nil {
+		return lossless.String(), nil
+	}
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil {
+		return "", err
+	}
+	for name := range fields {
+		switch name {
+		case "scheme", "host", "path", "query", "fragment":
+		default:
+			return "", losslessErr
+		}
+	}
+	return "", err
+}
+
+// decodeJSONStrict decode JSON object into v, unknown field and trailing data rejected
+func decodeJSONStrict(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return fmt.Errorf("unexpected data after JSON object")
+	}
+	return nil
+}
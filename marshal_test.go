@@ -0,0 +1,347 @@
+package uruki
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+var (
+	_ encoding.TextMarshaler     = (*Builder)(nil)
+	_ encoding.TextUnmarshaler   = (*Builder)(nil)
+	_ encoding.BinaryMarshaler   = (*Builder)(nil)
+	_ encoding.BinaryUnmarshaler = (*Builder)(nil)
+	_ json.Marshaler             = (*Builder)(nil)
+	_ json.Unmarshaler           = (*Builder)(nil)
+	_ gob.GobEncoder             = (*Builder)(nil)
+	_ gob.GobDecoder             = (*Builder)(nil)
+	_ sql.Scanner                = (*Builder)(nil)
+	_ driver.Valuer              = (*Builder)(nil)
+)
+
+type marshalConfig struct {
+	Link *Builder `json:"link"`
+}
+
+func Test_MarshalJSON(t *testing.T) {
+	type args struct {
+		name   string
+		format JSONFormat
+		want   string
+	}
+
+	rawURL := "https://www.tokopedia.com/search?q=macbook+air&st=product#review"
+	testCases := []args{
+		{
+			name: "json string",
+			want: `{"link":"https://www.tokopedia.com/search?q=macbook+air\u0026st=product#review"}`,
+		},
+		{
+			name:   "json components",
+			format: JSONComponents,
+			want: `{"link":{"scheme":"https","host":"www.tokopedia.com","path":"/search",` +
+				`"query":[{"key":"q","value":"macbook air"},{"key":"st","value":"product"}],"fragment":"review"}}`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ub, err := NewBuilder(Option{URL: rawURL, JSONFormat: tt.format})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			data, err := json.Marshal(marshalConfig{Link: ub})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if string(data) != tt.want {
+				t.Errorf("fail test MarshalJSON() got %v want %v", string(data), tt.want)
+			}
+			var decoded marshalConfig
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Error(err)
+				return
+			}
+			if got := decoded.Link.GetURLResult(); got != rawURL {
+				t.Errorf("fail test UnmarshalJSON() got %v want %v", got, rawURL)
+			}
+		})
+	}
+}
+
+func Test_UnmarshalDecodingContext(t *testing.T) {
+	type args struct {
+		name        string
+		data        string
+		want        string
+		wantErr     error
+		wantTypeErr bool
+	}
+
+	testCases := []args{
+		{
+			name: "allowed scheme",
+			data: `{"link":"tokopedia://product/123"}`,
+			want: "tokopedia://product/123",
+		},
+		{
+			name:    "restricted scheme",
+			data:    `{"link":"http://www.tokopedia.com"}`,
+			want:    "https://www.tokopedia.com",
+			wantErr: ErrorInvalidSchemeURI,
+		},
+		{
+			name:    "restricted scheme of components",
+			data:    `{"link":{"scheme":"javascript","path":"alert(1)"}}`,
+			want:    "https://www.tokopedia.com",
+			wantErr: ErrorInvalidSchemeURI,
+		},
+		{
+			name:        "invalid json",
+			data:        `{"link":123}`,
+			want:        "https://www.tokopedia.com",
+			wantTypeErr: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			// builder options used as decoding context
+			ctx, _ := NewBuilder(Option{URL: "https://www.tokopedia.com", RestrictScheme: []string{"https", "tokopedia"}})
+			cfg := marshalConfig{Link: ctx}
+			err := json.Unmarshal([]byte(tt.data), &cfg)
+			var jsonErr *json.UnmarshalTypeError
+			if tt.wantTypeErr != errors.As(err, &jsonErr) {
+				t.Errorf("fail error test UnmarshalJSON() got %v want type error %v", err, tt.wantTypeErr)
+			}
+			if !tt.wantTypeErr && !errors.Is(err, tt.wantErr) {
+				t.Errorf("fail error test UnmarshalJSON() got %v want %v", err, tt.wantErr)
+			}
+			if got := cfg.Link.GetURLResult(); got != tt.want {
+				t.Errorf("fail test UnmarshalJSON() got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_UnmarshalJSONNull(t *testing.T) {
+	ub, _ := NewBuilder(Option{URL: "https://www.tokopedia.com"})
+	if err := ub.UnmarshalJSON([]byte("null")); err != nil || ub.GetURLResult() != "https://www.tokopedia.com" {
+		t.Errorf("fail test UnmarshalJSON() null got %v %v want %v", ub.GetURLResult(), err, "https://www.tokopedia.com")
+	}
+}
+
+func Test_MarshalBinaryGob(t *testing.T) {
+	rawURL := "https://user@www.tokopedia.com/cart?whid=13355454"
+	ub, err := NewBuilder(Option{URL: rawURL})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(marshalConfig{Link: ub}); err != nil {
+		t.Error(err)
+		return
+	}
+	var decoded marshalConfig
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Error(err)
+		return
+	}
+	if got := decoded.Link.GetURLResult(); got != rawURL {
+		t.Errorf("fail test GobDecode() got %v want %v", got, rawURL)
+	}
+
+	text, _ := ub.MarshalText()
+	var fromText Builder
+	if err := fromText.UnmarshalText(text); err != nil || fromText.GetURLResult() != rawURL {
+		t.Errorf("fail test UnmarshalText() got %v %v want %v", fromText.GetURLResult(), err, rawURL)
+	}
+
+	var fromBinary Builder
+	if err := fromBinary.UnmarshalBinary([]byte("https://www.tokopedia.com")); !errors.Is(err, ErrorBinaryInvalid) {
+		t.Errorf("fail error test UnmarshalBinary() got %v want %v", err, ErrorBinaryInvalid)
+	}
+}
+
+func Test_SQL(t *testing.T) {
+	type args struct {
+		name    string
+		src     any
+		want    string
+		wantErr error
+	}
+
+	testCases := []args{
+		{name: "string", src: "https://www.tokopedia.com/cart", want: "https://www.tokopedia.com/cart"},
+		{name: "bytes", src: []byte("tokopedia://home"), want: "tokopedia://home"},
+		{name: "null", src: nil, want: ""},
+		{name: "restricted scheme", src: "ftp://www.tokopedia.com", want: "https://www.tokopedia.com", wantErr: ErrorInvalidSchemeURI},
+		{name: "unsupported type", src: int64(1), want: "https://www.tokopedia.com", wantErr: ErrorScanType},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ub, _ := NewBuilder(Option{URL: "https://www.tokopedia.com", RestrictScheme: []string{"https", "tokopedia"}})
+			err := ub.Scan(tt.src)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("fail error test Scan() got %v want %v", err, tt.wantErr)
+			}
+			value, err := ub.Value()
+			if err != nil || value != tt.want {
+				t.Errorf("fail test Value() got %v want %v", value, tt.want)
+			}
+		})
+	}
+
+	var ub *Builder
+	if value, err := ub.Value(); value != nil || err != nil {
+		t.Errorf("fail test Value() nil builder got %v want %v", value, nil)
+	}
+}
+
+func Test_MarshalJSONLosslessComponents(t *testing.T) {
+	rawURL := "https://www.tokopedia.com/search?q=macbook+air&caption=gopaylater%20-%20cicil&debug#review"
+	ub, err := NewBuilder(Option{URL: rawURL, JSONFormat: JSONLosslessComponents})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	data, err := json.Marshal(marshalConfig{Link: ub})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	want := `{"link":{"scheme":"https","host":"www.tokopedia.com","segments":["search"],"query":[{"key":"q","value":"macbook air"},` +
+		`{"key":"caption","value":"gopaylater - cicil","spaceEnc":"%20"},{"key":"debug","value":"","noValue":true}],"fragment":"review"}}`
+	if string(data) != want {
+		t.Errorf("fail test MarshalJSON() got %v want %v", string(data), want)
+	}
+	var decoded marshalConfig
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Error(err)
+		return
+	}
+	if got := decoded.Link.GetURLResult(); got != rawURL {
+		t.Errorf("fail test UnmarshalJSON() got %v want %v", got, rawURL)
+	}
+}
+
+func Test_UnmarshalJSONComponents(t *testing.T) {
+	type args struct {
+		name    string
+		data    string
+		want    string
+		wantErr bool
+	}
+
+	rawURL := "https://www.tokopedia.com"
+	testCases := []args{
+		{
+			name: "component object with path",
+			data: `{"scheme":"https","host":"x.com","path":"/a/b","query":[{"key":"q","value":"1"}]}`,
+			want: "https://x.com/a/b?q=1",
+		},
+		{
+			name: "lossless component object",
+			data: `{"scheme":"https","host":"x.com","segments":["a","b"],"query":[{"key":"debug","value":"","noValue":true}]}`,
+			want: "https://x.com/a/b?debug",
+		},
+		{
+			name: "lossless query entry only",
+			data: `{"scheme":"https","host":"x.com","query":[{"key":"q","value":"a b","spaceEnc":"%20"}]}`,
+			want: "https://x.com?q=a%20b",
+		},
+		{
+			name:    "unknown field",
+			data:    `{"scheme":"https","host":"x.com","pth":"/a"}`,
+			want:    rawURL,
+			wantErr: true,
+		},
+		{
+			name:    "unknown field of query",
+			data:    `{"scheme":"https","host":"x.com","path":"/a","query":[{"key":"q","val":"1"}]}`,
+			want:    rawURL,
+			wantErr: true,
+		},
+		{
+			name:    "path mixed with segments",
+			data:    `{"scheme":"https","host":"x.com","path":"/a","segments":["b"]}`,
+			want:    rawURL,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ub, _ := NewBuilder(Option{URL: rawURL})
+			err := ub.UnmarshalJSON([]byte(tt.data))
+			var e *Error
+			if tt.wantErr != errors.As(err, &e) {
+				t.Errorf("fail error test UnmarshalJSON() got %v want error %v", err, tt.wantErr)
+			}
+			if got := ub.GetURLResult(); got != tt.want {
+				t.Errorf("fail test UnmarshalJSON() got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_MarshalJSONLosslessRoundTrip(t *testing.T) {
+	type args struct {
+		name        string
+		rawURL      string
+		spaceEncode string
+	}
+
+	testCases := []args{
+		{
+			name:   "ipv6 host only",
+			rawURL: "https://[::1]",
+		},
+		{
+			name:        "plus space with default percent space",
+			rawURL:      "https://x.com?q=a+b",
+			spaceEncode: "%20",
+		},
+		{
+			name:   "fragment only",
+			rawURL: "https://x.com#a%20b",
+		},
+		{
+			name:   "no value query",
+			rawURL: "https://x.com/a?debug&q=1",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ub, err := NewBuilder(Option{URL: tt.rawURL, JSONFormat: JSONLosslessComponents, DefaultSpaceEncode: tt.spaceEncode})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			data, err := ub.MarshalJSON()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			decoded, _ := NewBuilder(Option{JSONFormat: JSONLosslessComponents, DefaultSpaceEncode: tt.spaceEncode})
+			if err := decoded.UnmarshalJSON(data); err != nil {
+				t.Errorf("fail test UnmarshalJSON() %s got %v want %v", data, err, nil)
+				return
+			}
+			if got := decoded.GetURLResult(); got != tt.rawURL {
+				t.Errorf("fail test UnmarshalJSON() %s got %v want %v", data, got, tt.rawURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package uruki
+
+import (
+	"net/url"
+	"strings"
+)
+
+// NestedQuery get child builder of encoded query or url carried inside query value, example extParam=ivf%3Dfalse%26src%3Dsearch
+// or redirect=https%3A%2F%2F... child keep encoding options of parent without scheme restriction,
+// and can be nested again for deeper level. write back the child with SetNestedQuery
+func (ub *Builder) NestedQuery(key string) (*Builder, error) {
+	val, ok := lookupQueryPair(parseRawQueryPairs(ub.url.RawQuery), key)
+	if !ok {
+		return nil, &Error{Op: "NestedQuery", Component: "query", Input: key, Err: ErrorKeyNotFound}
+	}
+	uri := &url.URL{RawQuery: val}
+	if isNestedURL(val) {
+		parsed, err := url.Parse(val)
+		if err != nil {
+			return nil, &Error{Op: "NestedQuery", Component: "query", Input: val, Err: err}
+		}
+		uri = parsed
+	}
+	child := ub.derive(uri)
+	child.restrictedScheme = map[string]bool{}
+	return child, nil
+}
+
+// SetNestedQuery write child builder back into query value of key with full encoding, first existing key replaced in place
+// otherwise appended. child with query only written without '?', nil child return ErrorChildNil
+func (ub *Builder) SetNestedQuery(key string, child *Builder) error {
+	if child == nil || child.url == nil {
+		return &Error{Op: "SetNestedQuery", Component: "query", Input: key, Err: ErrorChildNil}
+	}
+	return ub.mutate("SetNestedQuery", []string{key, child.url.String()}, func() error {
+		key = strings.TrimSpace(key)
+		if err := checkQueryKey("SetNestedQuery", key); err != nil {
+			return err
+		}
+		val := child.url.String()
+		if isQueryOnlyURL(child.url) {
+			val = child.url.RawQuery
+		}
+		spaceEnc := ub.defaultSpaceEncode
+		if len(spaceEnc) < 1 {
+			spaceEnc = PlusEncoding
+		}
+		ub.setRawQueryValue(key, escapeQuery(val, spaceEnc))
+		return nil
+	})
+}
+
+// isNestedURL check nested value is url (has scheme or started with slash) instead of plain query
+func isNestedURL(val string) bool {
+	if strings.HasPrefix(val, "/") || strings.HasPrefix(val, "?") || strings.HasPrefix(val, "#") {
+		return true
+	}
+	_, _, ok := splitScheme(val)
+	return ok
+}
+
+// isQueryOnlyURL check url only contains query
+func isQueryOnlyURL(uri *url.URL) bool {
+	return len(uri.Scheme) < 1 && len(uri.Opaque) < 1 && uri.User == nil && len(uri.Host) < 1 &&
+		len(uri.Path) < 1 && len(uri.Fragment) < 1
+}
@@ -0,0 +1,98 @@
+package uruki
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_NestedQuery(t *testing.T) {
+	ub, err := NewBuilder(Option{
+		URL: "https://www.tokopedia.com/acmic/acmic-usb-c-to-lightning-adapter?extParam=ivf%3Dfalse%26src%3Dsearch%26whid%3D13355454&redirect=https%3A%2F%2Fm.tokopedia.com%2Fcart%3Fnext%3Dpromo%253Dflash%2526code%253DA1",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ext, err := ub.NestedQuery("extParam")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got := ext.GetValueQuery("whid"); got != "13355454" {
+		t.Errorf("fail test NestedQuery() got %v want %v", got, "13355454")
+	}
+	ext.DeleteKeyQuery("src")
+	if err := ext.AddQueryParam(AddQueryParamOpt{Key: "src", Val: "home banner", SpaceEnc: PercentTwentyEncoding}); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := ub.SetNestedQuery("extParam", ext); err != nil {
+		t.Error(err)
+		return
+	}
+
+	// three level deep: redirect -> next -> promo
+	redirect, err := ub.NestedQuery("redirect")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got := redirect.GetFullPath(); got != "/cart" {
+		t.Errorf("fail test NestedQuery() got %v want %v", got, "/cart")
+	}
+	next, err := redirect.NestedQuery("next")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if got := next.GetValueQuery("promo"); got != "flash" {
+		t.Errorf("fail test NestedQuery() got %v want %v", got, "flash")
+	}
+	next.DeleteKeyQuery("code")
+	if err := redirect.SetNestedQuery("next", next); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := ub.SetNestedQuery("redirect", redirect); err != nil {
+		t.Error(err)
+		return
+	}
+
+	want := "https://www.tokopedia.com/acmic/acmic-usb-c-to-lightning-adapter?extParam=ivf%3Dfalse%26whid%3D13355454%26src%3Dhome%2520banner&redirect=https%3A%2F%2Fm.tokopedia.com%2Fcart%3Fnext%3Dpromo%253Dflash"
+	if got := ub.GetURLResult(); got != want {
+		t.Errorf("fail test SetNestedQuery() got %v want %v", got, want)
+	}
+
+	if _, err := ub.NestedQuery("missing"); !errors.Is(err, ErrorKeyNotFound) {
+		t.Errorf("fail test NestedQuery() got %v want %v", err, ErrorKeyNotFound)
+	}
+	var e *Error
+	if err := ub.SetNestedQuery("redirect", nil); !errors.Is(err, ErrorChildNil) || !errors.As(err, &e) {
+		t.Errorf("fail test SetNestedQuery() nil child got %v want %v", err, ErrorChildNil)
+	}
+	if got := ub.GetURLResult(); got != want {
+		t.Errorf("fail test SetNestedQuery() nil child got %v want %v", got, want)
+	}
+}
+
+func Test_SetNestedQueryAppend(t *testing.T) {
+	ub, err := NewBuilder(Option{URL: "https://www.tokopedia.com/login"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	child, err := NewBuilder(Option{URL: "https://www.tokopedia.com/cart?ref=login"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := ub.SetNestedQuery("next", child); err != nil {
+		t.Error(err)
+		return
+	}
+	want := "https://www.tokopedia.com/login?next=https%3A%2F%2Fwww.tokopedia.com%2Fcart%3Fref%3Dlogin"
+	if got := ub.GetURLResult(); got != want {
+		t.Errorf("fail test SetNestedQuery() got %v want %v", got, want)
+	}
+}
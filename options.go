@@ -0,0 +1,246 @@
+package uruki
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BuilderOption functional option to create new Builder, see New
+type BuilderOption func(c *builderConfig) error
+
+// builderConfig options collected from functional options with value of each setting set
+type builderConfig struct {
+	opt Option
+	set map[string]string
+}
+
+// TokopediaDeepLink preset for tokopedia:// app deep link, existing query escaped with %20 as space
+var TokopediaDeepLink = Preset(
+	WithRestrictSchemes("tokopedia"),
+	WithSpaceEncoding(PercentTwentyEncoding),
+	WithAutoEscape(),
+)
+
+// New create builder from functional options. the same setting can be repeated only with the same value,
+// otherwise ErrorOptionConflict returned
+func New(options ...BuilderOption) (*Builder, error) {
+	c := &builderConfig{set: map[string]string{}}
+	for _, option := range options {
+		if err := option(c); err != nil {
+			return nil, &Error{Op: "New", Component: "option", Err: err}
+		}
+	}
+	if _, ok := c.set["MalformedPolicy"]; ok && !c.opt.UseEscapeAutomateURL {
+		return nil, &Error{Op: "New", Component: "option", Err: fmt.Errorf("%w: MalformedPolicy require WithAutoEscape", ErrorOptionConflict)}
+	}
+	return NewBuilder(c.opt)
+}
+
+// Preset compose options into one reusable option
+func Preset(options ...BuilderOption) BuilderOption {
+	return func(c *builderConfig) error {
+		for _, option := range options {
+			if err := option(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// WithOption use Option struct as functional option, only non zero field set. values taken as is like NewBuilder,
+// only conflict with other options checked
+func WithOption(opt Option) BuilderOption {
+	return func(c *builderConfig) error {
+		var err error
+		set := func(name, value string, apply func()) {
+			if err == nil {
+				apply()
+				err = c.setting(name, value)
+			}
+		}
+		if len(opt.URL) > 0 {
+			set("URL", opt.URL, func() { c.opt.URL = opt.URL })
+		}
+		if len(opt.RestrictScheme) > 0 {
+			schemes := normalizeSchemes(opt.RestrictScheme)
+			set("RestrictSchemes", strings.Join(schemes, ","), func() { c.opt.RestrictScheme = schemes })
+		}
+		if len(opt.DefaultSpaceEncode) > 0 {
+			set("SpaceEncoding", opt.DefaultSpaceEncode, func() { c.opt.DefaultSpaceEncode = opt.DefaultSpaceEncode })
+		}
+		if opt.UseEscapeAutomateURL {
+			set("AutoEscape", "true", func() { c.opt.UseEscapeAutomateURL = true })
+		}
+		if opt.EncodeSet != EncodeDefault {
+			set("EncodeSet", strconv.Itoa(int(opt.EncodeSet)), func() { c.opt.EncodeSet = opt.EncodeSet })
+		}
+		if opt.ParseMode != NetURL {
+			set("ParseMode", strconv.Itoa(int(opt.ParseMode)), func() { c.opt.ParseMode = opt.ParseMode })
+		}
+		if opt.Strict {
+			set("Strict", "true", func() { c.opt.Strict = true })
+		}
+		if opt.MalformedPolicy != MalformedKeepRaw {
+			set("MalformedPolicy", strconv.Itoa(int(opt.MalformedPolicy)), func() { c.opt.MalformedPolicy = opt.MalformedPolicy })
+		}
+		if opt.HistorySize > 0 {
+			set("History", strconv.Itoa(opt.HistorySize), func() { c.opt.HistorySize = opt.HistorySize })
+		}
+		if opt.JSONFormat != JSONString {
+			set("JSONFormat", strconv.Itoa(int(opt.JSONFormat)), func() { c.opt.JSONFormat = opt.JSONFormat })
+		}
+		if err == nil {
+			c.opt.Hooks = append(c.opt.Hooks, opt.Hooks...)
+		}
+		return err
+	}
+}
+
+// WithURL url to proceed
+func WithURL(uri string) BuilderOption {
+	return func(c *builderConfig) error {
+		c.opt.URL = uri
+		return c.setting("URL", uri)
+	}
+}
+
+// WithRestrictSchemes restrict url scheme into given schemes
+func WithRestrictSchemes(schemes ...string) BuilderOption {
+	return func(c *builderConfig) error {
+		normalized := normalizeSchemes(schemes)
+		c.opt.RestrictScheme = normalized
+		return c.setting("RestrictSchemes", strings.Join(normalized, ","))
+	}
+}
+
+// WithSpaceEncoding default space encoding, one of SpaceEncoding const
+func WithSpaceEncoding(spaceEnc string) BuilderOption {
+	return func(c *builderConfig) error {
+		switch spaceEnc {
+		case WithoutEncoding, PercentTwentyEncoding, PlusEncoding:
+		default:
+			return fmt.Errorf("%w: space encoding %q", ErrorOptionInvalid, spaceEnc)
+		}
+		c.opt.DefaultSpaceEncode = spaceEnc
+		return c.setting("SpaceEncoding", spaceEnc)
+	}
+}
+
+// WithAutoEscape automate query escape on existing url query parameter, see UseEscapeAutomateURL
+func WithAutoEscape() BuilderOption {
+	return func(c *builderConfig) error {
+		c.opt.UseEscapeAutomateURL = true
+		return c.setting("AutoEscape", "true")
+	}
+}
+
+// WithEncodeSet percent-encode set used by setters
+func WithEncodeSet(set EncodeSet) BuilderOption {
+	return func(c *builderConfig) error {
+		if set < EncodeDefault || set > EncodeWHATWG {
+			return fmt.Errorf("%w: encode set %d", ErrorOptionInvalid, set)
+		}
+		c.opt.EncodeSet = set
+		return c.setting("EncodeSet", strconv.Itoa(int(set)))
+	}
+}
+
+// WithParseMode parser used to parse url
+func WithParseMode(mode ParseMode) BuilderOption {
+	return func(c *builderConfig) error {
+		if mode < NetURL || mode > WHATWG {
+			return fmt.Errorf("%w: parse mode %d", ErrorOptionInvalid, mode)
+		}
+		c.opt.ParseMode = mode
+		return c.setting("ParseMode", strconv.Itoa(int(mode)))
+	}
+}
+
+// WithStrict reject url not valid by RFC 3986, see Validate
+func WithStrict() BuilderOption {
+	return func(c *builderConfig) error {
+		c.opt.Strict = true
+		return c.setting("Strict", "true")
+	}
+}
+
+// WithMalformedPolicy malformed percent escape policy of auto escape, require WithAutoEscape
+func WithMalformedPolicy(policy MalformedPolicy) BuilderOption {
+	return func(c *builderConfig) error {
+		if policy < MalformedKeepRaw || policy > MalformedError {
+			return fmt.Errorf("%w: malformed policy %d", ErrorOptionInvalid, policy)
+		}
+		c.opt.MalformedPolicy = policy
+		return c.setting("MalformedPolicy", strconv.Itoa(int(policy)))
+	}
+}
+
+// WithHistory record at most size mutations for Undo / Redo, see Option HistorySize
+func WithHistory(size int) BuilderOption {
+	return func(c *builderConfig) error {
+		if size < 1 {
+			return fmt.Errorf("%w: history size %d", ErrorOptionInvalid, size)
+		}
+		c.opt.HistorySize = size
+		return c.setting("History", strconv.Itoa(size))
+	}
+}
+
+// WithHooks register hooks notified on every change of url, hooks of repeated option appended. see Hook
+func WithHooks(hooks ...Hook) BuilderOption {
+	return func(c *builderConfig) error {
+		c.opt.Hooks = append(c.opt.Hooks, hooks...)
+		return nil
+	}
+}
+
+// WithJSONFormat format of builder encoded as JSON
+func WithJSONFormat(format JSONFormat) BuilderOption {
+	return func(c *builderConfig) error {
+		if format < JSONString || format > JSONLosslessComponents {
+			return fmt.Errorf("%w: json format %d", ErrorOptionInvalid, format)
+		}
+		c.opt.JSONFormat = format
+		return c.setting("JSONFormat", strconv.Itoa(int(format)))
+	}
+}
+
+// mergeOptions merge options of NewBuilder into one option with WithOption, values taken as is like single option.
+// the same setting repeated with different value return ErrorOptionConflict
+func mergeOptions(options []Option) (Option, error) {
+	if len(options) < 1 {
+		return Option{}, nil
+	}
+	if len(options) == 1 {
+		return options[0], nil
+	}
+	c := &builderConfig{set: map[string]string{}}
+	for _, opt := range options {
+		if err := WithOption(opt)(c); err != nil {
+			return Option{}, err
+		}
+	}
+	return c.opt, nil
+}
+
+// normalizeSchemes lower cased and sorted schemes, compared as setting value
+func normalizeSchemes(schemes []string) []string {
+	normalized := make([]string, 0, len(schemes))
+	for _, v := range schemes {
+		normalized = append(normalized, strings.ToLower(strings.TrimSpace(v)))
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// setting record value of setting, error when already set with different value
+func (c *builderConfig) setting(name, value string) error {
+	if prev, ok := c.set[name]; ok && prev != value {
+		return fmt.Errorf("%w: %s set to %q and %q", ErrorOptionConflict, name, prev, value)
+	}
+	c.set[name] = value
+	return nil
+}
@@ -0,0 +1,168 @@
+package uruki
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_New(t *testing.T) {
+	type args struct {
+		name    string
+		options []BuilderOption
+		want    string
+		wantErr error
+	}
+
+	rawURL := "https://www.tokopedia.com/search?st=product&q=macbook air m2"
+	testCases := []args{
+		{
+			name:    "functional options",
+			options: []BuilderOption{WithURL(rawURL), WithRestrictSchemes("HTTPS", "tokopedia"), WithSpaceEncoding(PlusEncoding), WithAutoEscape()},
+			want:    "https://www.tokopedia.com/search?st=product&q=macbook+air+m2",
+		},
+		{
+			name:    "old option struct keep working",
+			options: []BuilderOption{WithOption(Option{URL: rawURL, DefaultSpaceEncode: PercentTwentyEncoding, UseEscapeAutomateURL: true})},
+			want:    "https://www.tokopedia.com/search?st=product&q=macbook%20air%20m2",
+		},
+		{
+			name:    "preset",
+			options: []BuilderOption{TokopediaDeepLink, WithURL("tokopedia://webview?caption=gopaylater - cicil")},
+			want:    "tokopedia://webview?caption=gopaylater%20-%20cicil",
+		},
+		{
+			name:    "preset repeated with the same value",
+			options: []BuilderOption{TokopediaDeepLink, WithAutoEscape(), WithSpaceEncoding(PercentTwentyEncoding), WithURL("tokopedia://home")},
+			want:    "tokopedia://home",
+		},
+		{
+			name:    "preset restrict scheme",
+			options: []BuilderOption{TokopediaDeepLink, WithURL(rawURL)},
+			wantErr: ErrorInvalidSchemeURI,
+		},
+		{
+			name:    "conflict space encoding with preset",
+			options: []BuilderOption{TokopediaDeepLink, WithSpaceEncoding(PlusEncoding)},
+			wantErr: ErrorOptionConflict,
+		},
+		{
+			name:    "conflict restrict schemes",
+			options: []BuilderOption{WithRestrictSchemes("https"), WithOption(Option{RestrictScheme: []string{"http"}})},
+			wantErr: ErrorOptionConflict,
+		},
+		{
+			name:    "malformed policy without auto escape",
+			options: []BuilderOption{WithURL(rawURL), WithMalformedPolicy(MalformedError)},
+			wantErr: ErrorOptionConflict,
+		},
+		{
+			name:    "invalid space encoding",
+			options: []BuilderOption{WithSpaceEncoding("%2B")},
+			wantErr: ErrorOptionInvalid,
+		},
+		{
+			name:    "invalid parse mode",
+			options: []BuilderOption{WithParseMode(ParseMode(9))},
+			wantErr: ErrorOptionInvalid,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ub, err := New(tt.options...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("fail test New() got error %v want %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got := ub.GetURLResult(); got != tt.want {
+				t.Errorf("fail test New() got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_NewBuilderMultipleOptions(t *testing.T) {
+	type args struct {
+		name    string
+		build   func(options ...Option) (*Builder, error)
+		options []Option
+		want    string
+		wantErr error
+	}
+
+	fromComponents := func(options ...Option) (*Builder, error) {
+		return FromComponents(Components{Scheme: "http", Host: "www.tokopedia.com"}, options...)
+	}
+	fromURL := func(options ...Option) (*Builder, error) {
+		u, _ := ParseURL("http://www.tokopedia.com")
+		return u.Builder(options...)
+	}
+	fromChain := func(options ...Option) (*Builder, error) {
+		return From("http://www.tokopedia.com", options...).Build()
+	}
+	testCases := []args{
+		{
+			name:    "merged options",
+			build:   NewBuilder,
+			options: []Option{{URL: "https://www.tokopedia.com/search?q=macbook air"}, {DefaultSpaceEncode: PercentTwentyEncoding, UseEscapeAutomateURL: true}},
+			want:    "https://www.tokopedia.com/search?q=macbook%20air",
+		},
+		{
+			name:    "restrict scheme of second option",
+			build:   NewBuilder,
+			options: []Option{{URL: "http://www.tokopedia.com"}, {RestrictScheme: []string{"https"}}},
+			wantErr: ErrorInvalidSchemeURI,
+		},
+		{
+			name:    "single option value taken as is",
+			build:   NewBuilder,
+			options: []Option{{URL: "https://www.tokopedia.com", DefaultSpaceEncode: "%2B"}},
+			want:    "https://www.tokopedia.com",
+		},
+		{
+			name:    "merged option value taken as is",
+			build:   NewBuilder,
+			options: []Option{{URL: "https://www.tokopedia.com"}, {DefaultSpaceEncode: "%2B"}},
+			want:    "https://www.tokopedia.com",
+		},
+		{
+			name:    "conflict",
+			build:   NewBuilder,
+			options: []Option{{URL: "https://www.tokopedia.com", DefaultSpaceEncode: PlusEncoding}, {DefaultSpaceEncode: PercentTwentyEncoding}},
+			wantErr: ErrorOptionConflict,
+		},
+		{
+			name:    "from components",
+			build:   fromComponents,
+			options: []Option{{}, {RestrictScheme: []string{"https"}}},
+			wantErr: ErrorInvalidSchemeURI,
+		},
+		{
+			name:    "url builder",
+			build:   fromURL,
+			options: []Option{{}, {RestrictScheme: []string{"https"}}},
+			wantErr: ErrorInvalidSchemeURI,
+		},
+		{
+			name:    "chain",
+			build:   fromChain,
+			options: []Option{{HistorySize: 2}, {HistorySize: 3}},
+			wantErr: ErrorOptionConflict,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ub, err := tt.build(tt.options...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("fail error test NewBuilder() got %v want %v", err, tt.wantErr)
+			}
+			if err == nil && ub.GetURLResult() != tt.want {
+				t.Errorf("fail test NewBuilder() got %v want %v", ub.GetURLResult(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package uruki
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AWS Signature Version 4 constants
+const (
+	SigV4Algorithm       = "AWS4-HMAC-SHA256"
+	SigV4UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+	sigV4TimeFormat  = "20060102T150405Z"
+	sigV4DateFormat  = "20060102"
+	sigV4Terminator  = "aws4_request"
+	sigV4MaxExpires  = 7 * 24 * time.Hour
+	sigV4DefaultLife = 15 * time.Minute
+)
+
+// PresignV4Options options for PresignV4
+type PresignV4Options struct {
+	// AccessKeyID: aws access key id, required
+	AccessKeyID string
+	// SecretAccessKey: aws secret access key, required
+	SecretAccessKey string
+	// SessionToken: optional temporary credential token, added as X-Amz-Security-Token
+	SessionToken string
+	// Region: signing region, required. example "us-east-1"
+	Region string
+	// Service: signing service, default "s3"
+	Service string
+	// Method: http method of presigned request, default GET
+	Method string
+	// Expires: validity duration of presigned url, default 15 minutes, max 7 days
+	Expires time.Duration
+	// Headers: additional headers to sign, client must send the same headers. host always signed
+	Headers map[string]string
+	// PayloadHash: hex sha256 of payload, default SigV4UnsignedPayload
+	PayloadHash string
+	// Now: clock of signing time, default time.Now
+	Now func() time.Time
+}
+
+// PresignV4 presign url with AWS Signature Version 4 query string authentication (X-Amz-Algorithm, X-Amz-Credential,
+// X-Amz-Date, X-Amz-Expires, X-Amz-SignedHeaders and X-Amz-Signature). query rewritten in canonical encoding
+func (ub *Builder) PresignV4(opt PresignV4Options) error {
+	return ub.mutate("PresignV4", []string{opt.AccessKeyID, opt.Region, opt.Service}, func() error {
+		if len(opt.AccessKeyID) < 1 || len(opt.SecretAccessKey) < 1 || len(opt.Region) < 1 {
+			return &Error{Op: "PresignV4", Component: "credential", Input: opt.AccessKeyID, Err: ErrorPresignCredential}
+		}
+		if len(ub.url.Host) < 1 {
+			return &Error{Op: "PresignV4", Component: "host", Input: ub.url.String(), Err: ErrorPresignHost}
+		}
+		if len(opt.Service) < 1 {
+			opt.Service = "s3"
+		}
+		if len(opt.Method) < 1 {
+			opt.Method = "GET"
+		}
+		if opt.Expires == 0 {
+			opt.Expires = sigV4DefaultLife
+		}
+		if opt.Expires < time.Second || opt.Expires > sigV4MaxExpires {
+			return &Error{Op: "PresignV4", Component: "expires", Input: opt.Expires.String(), Err: ErrorPresignExpires}
+		}
+		if len(opt.PayloadHash) < 1 {
+			opt.PayloadHash = SigV4UnsignedPayload
+		}
+		now := time.Now
+		if opt.Now != nil {
+			now = opt.Now
+		}
+		signTime := now().UTC()
+
+		headers := map[string]string{"host": ub.url.Host}
+		for k, v := range opt.Headers {
+			headers[strings.ToLower(strings.TrimSpace(k))] = v
+		}
+		query := make([]queryPair, 0)
+		for _, v := range parseRawQueryPairs(ub.url.RawQuery) {
+			if !strings.HasPrefix(v.key, "X-Amz-") {
+				query = append(query, v)
+			}
+		}
+		query = append(query,
+			queryPair{"X-Amz-Algorithm", SigV4Algorithm},
+			queryPair{"X-Amz-Credential", opt.AccessKeyID + "/" + sigV4Scope(signTime, opt.Region, opt.Service)},
+			queryPair{"X-Amz-Date", signTime.Format(sigV4TimeFormat)},
+			queryPair{"X-Amz-Expires", strconv.Itoa(int(opt.Expires / time.Second))},
+			queryPair{"X-Amz-SignedHeaders", sigV4SignedHeaders(headers)},
+		)
+		if len(opt.SessionToken) > 0 {
+			query = append(query, queryPair{"X-Amz-Security-Token", opt.SessionToken})
+		}
+		signature := sigV4Signature(sigV4Request{
+			method:      opt.Method,
+			uri:         ub.url,
+			query:       query,
+			headers:     headers,
+			payloadHash: opt.PayloadHash,
+			secret:      opt.SecretAccessKey,
+			region:      opt.Region,
+			service:     opt.Service,
+			signTime:    signTime,
+		})
+		ub.url.RawQuery = sigV4CanonicalQuery(query) + ampersandStr + "X-Amz-Signature=" + signature
+		return nil
+	})
+}
+
+// sigV4Request parts of request covered by signature
+type sigV4Request struct {
+	method      string
+	uri         *url.URL
+	query       []queryPair
+	headers     map[string]string
+	payloadHash string
+	secret      string
+	region      string
+	service     string
+	signTime    time.Time
+}
+
+// sigV4Signature hex signature of request, see https://docs.aws.amazon.com/IAM/latest/UserGuide/create-signed-request.html
+func sigV4Signature(req sigV4Request) string {
+	canonicalRequest := strings.Join([]string{
+		req.method,
+		sigV4CanonicalURI(req.uri, req.service),
+		sigV4CanonicalQuery(req.query),
+		sigV4CanonicalHeaders(req.headers),
+		sigV4SignedHeaders(req.headers),
+		req.payloadHash,
+	}, "\n")
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		SigV4Algorithm,
+		req.signTime.Format(sigV4TimeFormat),
+		sigV4Scope(req.signTime, req.region, req.service),
+		hex.EncodeToString(hashed[:]),
+	}, "\n")
+	key := hmacSHA256([]byte("AWS4"+req.secret), req.signTime.Format(sigV4DateFormat))
+	key = hmacSHA256(key, req.region)
+	key = hmacSHA256(key, req.service)
+	key = hmacSHA256(key, sigV4Terminator)
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// sigV4Scope credential scope date/region/service/aws4_request
+func sigV4Scope(t time.Time, region, service string) string {
+	return t.Format(sigV4DateFormat) + "/" + region + "/" + service + "/" + sigV4Terminator
+}
+
+// sigV4CanonicalURI uri encoded path. s3 encode decoded path once, other services normalize path as written in url
+// by removing '.', '..' and empty segments then encode it once more
+func sigV4CanonicalURI(uri *url.URL, service string) string {
+	if service == "s3" {
+		if len(uri.Path) < 1 {
+			return "/"
+		}
+		return sigV4EscapePath(uri.Path)
+	}
+	rawPath := uri.RawPath
+	if len(rawPath) < 1 {
+		rawPath = uri.EscapedPath()
+	}
+	if len(rawPath) < 1 {
+		return "/"
+	}
+	normalized := path.Clean("/" + rawPath)
+	if strings.HasSuffix(rawPath, "/") && normalized != "/" {
+		normalized += "/"
+	}
+	return sigV4EscapePath(normalized)
+}
+
+// sigV4EscapePath uri encode each segment of path
+func sigV4EscapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, v := range segments {
+		segments[i] = sigV4Escape(v)
+	}
+	return strings.Join(segments, "/")
+}
+
+// sigV4CanonicalQuery uri encoded query sorted by key then value
+func sigV4CanonicalQuery(query []queryPair) string {
+	encoded := make([]queryPair, 0, len(query))
+	for _, v := range query {
+		encoded = append(encoded, queryPair{sigV4Escape(v.key), sigV4Escape(v.val)})
+	}
+	sort.SliceStable(encoded, func(i, j int) bool {
+		if encoded[i].key != encoded[j].key {
+			return encoded[i].key < encoded[j].key
+		}
+		return encoded[i].val < encoded[j].val
+	})
+	result := make([]string, 0, len(encoded))
+	for _, v := range encoded {
+		result = append(result, v.key+"="+v.val)
+	}
+	return strings.Join(result, ampersandStr)
+}
+
+// sigV4CanonicalHeaders lower cased headers sorted by name with trimmed & collapsed value, ended by new line
+func sigV4CanonicalHeaders(headers map[string]string) string {
+	names := sigV4HeaderNames(headers)
+	var sb strings.Builder
+	for _, name := range names {
+		sb.WriteString(name + ":" + strings.Join(strings.Fields(headers[name]), " ") + "\n")
+	}
+	return sb.String()
+}
+
+// sigV4SignedHeaders sorted header names joined by semicolon
+func sigV4SignedHeaders(headers map[string]string) string {
+	return strings.Join(sigV4HeaderNames(headers), ";")
+}
+
+// sigV4HeaderNames sorted header names
+func sigV4HeaderNames(headers map[string]string) []string {
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sigV4Escape uri encode every byte except unreserved characters A-Z a-z 0-9 - _ . ~
+func sigV4Escape(s string) string {
+	return percentEncode(s, isUnreserved, "", false)
+}
+
+// hmacSHA256 hmac sha256 digest of data
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
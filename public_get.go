@@ -31,9 +31,9 @@ func (ub *Builder) GetAllQueryValue() map[string][]string {
 	return ub.url.Query()
 }
 
-// GetInternalURL get internal url that already parsed by uruki
+// GetInternalURL get copy of internal url that already parsed by uruki, userinfo copied
 func (ub *Builder) GetInternalURL() url.URL {
-	return *ub.url
+	return *copyURL(ub.url)
 }
 
 // GetPaths get each part of path in slice
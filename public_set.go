@@ -15,6 +15,8 @@ type AddQueryParamOpt struct {
 	UseDefaultEncode bool
 	// specify space encode if you use custom encoding
 	SpaceEnc string
+	// percent-encode set of this call, default follow builder EncodeSet
+	EncodeSet EncodeSet
 }
 
 // SetFragmentOpt parameter for adding query params value in url
@@ -25,108 +27,157 @@ type SetFragmentOpt struct {
 	UseDefaultEncode bool
 	// specify space encode if you use custom encoding
 	SpaceEnc string
+	// percent-encode set of this call, default follow builder EncodeSet
+	EncodeSet EncodeSet
+}
+
+// SetPathSegmentsOpt parameter for set path from segments in url
+type SetPathSegmentsOpt struct {
+	// raw path segments, each segment escaped including '/'
+	Segments []string
+	// percent-encode set of this call, default follow builder EncodeSet
+	EncodeSet EncodeSet
 }
 
 // SetBaseURL change or update existing of base url only host and port
 func (ub *Builder) SetBaseURL(baseURL string) error {
-	uri, err := ub.parseURL(baseURL)
-	if err != nil {
-		return err
-	}
-	if len(uri.Host) < 1 && len(ub.url.Path) > 0 {
-		firstChar := ub.url.Path[0]
-		if firstChar == ("/"[0]) {
-			ub.url.Path = strings.Replace(ub.url.Path, "/", "", 1)
+	return ub.mutate("SetBaseURL", []string{baseURL}, func() error {
+		uri, err := ub.parseURL(baseURL)
+		if err != nil {
+			return withOp("SetBaseURL", baseURL, err)
 		}
-	}
-	ub.url.Host = uri.Host
-	ub.url.Scheme = uri.Scheme
-	return nil
+		if len(uri.Host) < 1 && len(ub.url.Path) > 0 {
+			firstChar := ub.url.Path[0]
+			if firstChar == ("/"[0]) {
+				ub.url.Path = strings.Replace(ub.url.Path, "/", "", 1)
+			}
+		}
+		ub.url.Host = uri.Host
+		ub.url.Scheme = uri.Scheme
+		return nil
+	})
 }
 
 // SetPath change or update path only of url
 func (ub *Builder) SetPath(path string) {
-	ub.url.Path = path
+	ub.mutate("SetPath", []string{path}, func() error {
+		ub.url.Path = path
+		ub.url.RawPath = ""
+		return nil
+	})
 }
 
 // SetURL replace all url with new url based on parameter, if error keep old url
 func (ub *Builder) SetURL(uri string) error {
-	err := ub.setURL(uri)
-	if err != nil {
-		return err
-	}
-	if ub.useEscapeAutomateURL {
-		ub.queryEscapeAutomate()
-	}
-	return nil
+	return ub.mutate("SetURL", []string{uri}, func() error {
+		if err := ub.replaceURL(uri); err != nil {
+			return withOp("SetURL", uri, err)
+		}
+		return nil
+	})
 }
 
 // AddQueryParam add query parameter values, internally will be encode the value of query
 func (ub *Builder) AddQueryParam(opt AddQueryParamOpt) error {
-	key := strings.TrimSpace(opt.Key)
-	if len(key) < 1 {
-		return ErrorKeyEmpty
-	}
-	if strings.Contains(key, " ") {
-		return ErrorKeyContainSpace
-	}
-	if opt.UseDefaultEncode {
-		opt.SpaceEnc = ub.defaultSpaceEncode
-	}
-	key = url.QueryEscape(key)
-	key = strings.ReplaceAll(key, PlusEncoding, opt.SpaceEnc)
-	value := url.QueryEscape(opt.Val)
-	value = strings.ReplaceAll(value, PlusEncoding, opt.SpaceEnc)
-	rawQuery := ub.url.RawQuery
-	if len(rawQuery) > 0 {
-		rawQuery += ampersandStr
-	}
-	ub.url.RawQuery = rawQuery + key + "=" + value
-	return nil
+	return ub.mutate("AddQueryParam", []string{opt.Key, opt.Val}, func() error {
+		key := strings.TrimSpace(opt.Key)
+		if err := checkQueryKey("AddQueryParam", key); err != nil {
+			return err
+		}
+		if opt.UseDefaultEncode {
+			opt.SpaceEnc = ub.defaultSpaceEncode
+		}
+		var value string
+		if enc := ub.encoder(opt.EncodeSet, opt.SpaceEnc); enc.Set != EncodeLegacy {
+			key = enc.Escape(key, ComponentQueryKey)
+			value = enc.Escape(opt.Val, ComponentQueryValue)
+		} else {
+			key = url.QueryEscape(key)
+			key = strings.ReplaceAll(key, PlusEncoding, opt.SpaceEnc)
+			value = url.QueryEscape(opt.Val)
+			value = strings.ReplaceAll(value, PlusEncoding, opt.SpaceEnc)
+		}
+		rawQuery := ub.url.RawQuery
+		if len(rawQuery) > 0 {
+			rawQuery += ampersandStr
+		}
+		ub.url.RawQuery = rawQuery + key + "=" + value
+		return nil
+	})
 }
 
 // DeleteFragment remove existing fragment if any
 func (ub *Builder) DeleteFragment() {
-	ub.url.Fragment = ""
+	ub.mutate("DeleteFragment", nil, func() error {
+		ub.url.Fragment = ""
+		ub.url.RawFragment = ""
+		return nil
+	})
 }
 
 // SetFragment create / update existing fragment for references, without '#'
 func (ub *Builder) SetFragment(opt SetFragmentOpt) {
-	value := url.QueryEscape(opt.Fragment)
-	if opt.UseDefaultEncode {
-		opt.SpaceEnc = ub.defaultSpaceEncode
-	}
-	value = strings.ReplaceAll(value, PercentTwentyEncoding, opt.SpaceEnc)
-	ub.url.Fragment = value
+	ub.mutate("SetFragment", []string{opt.Fragment}, func() error {
+		if opt.UseDefaultEncode {
+			opt.SpaceEnc = ub.defaultSpaceEncode
+		}
+		enc := ub.encoder(opt.EncodeSet, opt.SpaceEnc)
+		if enc.Set != EncodeLegacy {
+			rawFragment := enc.Escape(opt.Fragment, ComponentFragment)
+			fragment, err := url.PathUnescape(rawFragment)
+			if err != nil {
+				fragment = opt.Fragment
+			}
+			ub.url.Fragment = fragment
+			ub.url.RawFragment = rawFragment
+			return nil
+		}
+		value := url.QueryEscape(opt.Fragment)
+		value = strings.ReplaceAll(value, PercentTwentyEncoding, opt.SpaceEnc)
+		ub.url.Fragment = value
+		ub.url.RawFragment = ""
+		return nil
+	})
+}
+
+// SetPathSegments change or update path of url from raw segments, each segment escaped as path segment
+func (ub *Builder) SetPathSegments(opt SetPathSegmentsOpt) {
+	ub.mutate("SetPathSegments", append([]string{}, opt.Segments...), func() error {
+		enc := ub.encoder(opt.EncodeSet, "")
+		segments := make([]string, len(opt.Segments))
+		for i, v := range opt.Segments {
+			segments[i] = enc.Escape(v, ComponentPathSegment)
+		}
+		rawPath := "/" + strings.Join(segments, "/")
+		path, err := url.PathUnescape(rawPath)
+		if err != nil {
+			path = rawPath
+		}
+		ub.url.Path = path
+		ub.url.RawPath = rawPath
+		return nil
+	})
 }
 
-// DeleteKeyQuery delete key query parameter if exist
+// DeleteKeyQuery delete key query parameter if exist, other parameters kept as is including value with '=' and key without value
 func (ub *Builder) DeleteKeyQuery(keyDelete string) {
-	keyVal := strings.Split(ub.url.RawQuery, ampersandStr)
-	buildRawResult := make([]string, len(keyVal))
-	for i, queryParam := range keyVal {
-		qv := strings.Split(queryParam, "=")
-		if len(qv) > 0 {
-			q := qv[0]
-			v := ""
-			if len(qv) > 1 {
-				v = qv[1]
-			}
+	ub.mutate("DeleteKeyQuery", []string{keyDelete}, func() error {
+		keyVal := strings.Split(ub.url.RawQuery, ampersandStr)
+		cleanResult := []string{}
+		for _, queryParam := range keyVal {
+			q, _, _ := strings.Cut(queryParam, "=")
 			qDel, err := url.QueryUnescape(q)
 			if err != nil {
 				qDel = q
 			}
-			if qDel != keyDelete {
-				buildRawResult[i] = q + "=" + v
+			if qDel == keyDelete {
+				continue
+			}
+			if v := strings.TrimSpace(queryParam); v != "" {
+				cleanResult = append(cleanResult, v)
 			}
 		}
-	}
-	cleanResult := []string{}
-	for _, v := range buildRawResult {
-		v = strings.TrimSpace(v)
-		if v != "" {
-			cleanResult = append(cleanResult, v)
-		}
-	}
-	ub.url.RawQuery = strings.Join(cleanResult, ampersandStr)
+		ub.url.RawQuery = strings.Join(cleanResult, ampersandStr)
+		return nil
+	})
 }
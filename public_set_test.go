@@ -1,6 +1,9 @@
 package uruki
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func Test_AddQueryParam(t *testing.T) {
 	type args struct {
@@ -117,7 +120,7 @@ func Test_AddQueryParam(t *testing.T) {
 			}
 			for _, op := range tt.opt {
 				err := ub.AddQueryParam(op)
-				if err != tt.wantErr {
+				if !errors.Is(err, tt.wantErr) {
 					t.Errorf("fail error test AddQueryParam() got %v want %v", err, tt.wantErr)
 				}
 			}
@@ -144,6 +147,12 @@ func Test_DeleteKeyQuery(t *testing.T) {
 			url:          "https://www.tokopedia.com/search?st=product&q=produck%20p%26g&srp_component_id=01.07.00.00&srp_page_id=&srp_page_title=&navsource=&=exist_val_empty_key",
 			wantRawQuery: "https://www.tokopedia.com/search?q=produck%20p%26g&srp_component_id=01.07.00.00&srp_page_title=&=exist_val_empty_key",
 		},
+		{
+			name:         "delete key keep value with equal sign and key without value",
+			keyDelete:    []string{"page"},
+			url:          "https://x.com/dl?token=YWJj==&debug&page=1&data=a=b",
+			wantRawQuery: "https://x.com/dl?token=YWJj==&debug&data=a=b",
+		},
 		{
 			name:         "delete non exist key",
 			keyDelete:    []string{"refer"},
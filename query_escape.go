@@ -0,0 +1,95 @@
+package uruki
+
+import (
+	"net/url"
+	"strings"
+)
+
+// MalformedPolicy how UseEscapeAutomateURL handle key or value of existing query with malformed percent escape
+type MalformedPolicy int
+
+// constants of MalformedPolicy
+const (
+	// MalformedKeepRaw keep malformed key or value as is, other parameters still escaped
+	MalformedKeepRaw MalformedPolicy = iota
+	// MalformedEncodePercent percent encode stray '%' as %25 then escape key or value
+	MalformedEncodePercent
+	// MalformedError return ErrorMalformedQuery
+	MalformedError
+)
+
+// queryEscapeAutomate escape all query parameter from existing url, url kept as is on error
+func (ub *Builder) queryEscapeAutomate() error {
+	rawQuery, err := ub.escapeRawQuery(ub.url.RawQuery)
+	if err != nil {
+		return err
+	}
+	ub.url.RawQuery = rawQuery
+	return nil
+}
+
+// escapeRawQuery escape each key and value of raw query. value split on the first '=' only, empty key kept,
+// key without '=' kept without '=' and empty parameter kept
+func (ub *Builder) escapeRawQuery(rawQuery string) (string, error) {
+	if len(rawQuery) < 1 {
+		return rawQuery, nil
+	}
+	keyVal := strings.Split(rawQuery, ampersandStr)
+	for i, queryParam := range keyVal {
+		if len(queryParam) < 1 {
+			continue
+		}
+		q, v, hasValue := strings.Cut(queryParam, "=")
+		key, err := ub.escapeQueryPart(q)
+		if err != nil {
+			return "", &Error{Component: "query", Input: queryParam, Err: err}
+		}
+		if !hasValue {
+			keyVal[i] = key
+			continue
+		}
+		val, err := ub.escapeQueryPart(v)
+		if err != nil {
+			return "", &Error{Component: "query", Input: queryParam, Err: err}
+		}
+		keyVal[i] = key + "=" + val
+	}
+	return strings.Join(keyVal, ampersandStr), nil
+}
+
+// escapeQueryPart unescape then escape key or value of query with default space encoding or '+' when it is empty,
+// malformed escape handled by malformed policy
+func (ub *Builder) escapeQueryPart(raw string) (string, error) {
+	unescaped, err := url.QueryUnescape(raw)
+	if err != nil {
+		switch ub.malformedPolicy {
+		case MalformedError:
+			return "", ErrorMalformedQuery
+		case MalformedEncodePercent:
+			unescaped, err = url.QueryUnescape(encodeStrayPercent(raw))
+			if err != nil {
+				return "", ErrorMalformedQuery
+			}
+		default:
+			return raw, nil
+		}
+	}
+	spaceEnc := ub.defaultSpaceEncode
+	if len(spaceEnc) < 1 {
+		spaceEnc = PlusEncoding
+	}
+	return escapeQuery(unescaped, spaceEnc), nil
+}
+
+// encodeStrayPercent percent encode '%' not followed by two hex digits
+func encodeStrayPercent(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && (i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2])) {
+			sb.WriteString("%25")
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
@@ -0,0 +1,225 @@
+package uruki
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_QueryEscapeAutomate(t *testing.T) {
+	type args struct {
+		name        string
+		rawQuery    string
+		spaceEnc    string
+		keepRaw     string
+		encodeStray string
+		wantErr     bool
+	}
+
+	testCases := []args{
+		{
+			name:        "well formed query",
+			rawQuery:    "st=product&q=macbook air m2&fcity=174,175",
+			spaceEnc:    PercentTwentyEncoding,
+			keepRaw:     "st=product&q=macbook%20air%20m2&fcity=174%2C175",
+			encodeStray: "st=product&q=macbook%20air%20m2&fcity=174%2C175",
+		},
+		{
+			name:        "equal sign on value not truncated",
+			rawQuery:    "redirect=/cart?step=2&token=YWJj==",
+			spaceEnc:    PlusEncoding,
+			keepRaw:     "redirect=%2Fcart%3Fstep%3D2&token=YWJj%3D%3D",
+			encodeStray: "redirect=%2Fcart%3Fstep%3D2&token=YWJj%3D%3D",
+		},
+		{
+			name:        "empty key, key without equal sign and empty parameter",
+			rawQuery:    "=orphan&debug&&ref=",
+			spaceEnc:    PlusEncoding,
+			keepRaw:     "=orphan&debug&&ref=",
+			encodeStray: "=orphan&debug&&ref=",
+		},
+		{
+			name:        "stray percent on discount value",
+			rawQuery:    "promo=diskon 50%&q=kopi",
+			spaceEnc:    PlusEncoding,
+			keepRaw:     "promo=diskon 50%&q=kopi",
+			encodeStray: "promo=diskon+50%25&q=kopi",
+			wantErr:     true,
+		},
+		{
+			name:        "malformed key keep key instead of value",
+			rawQuery:    "utm_%zzsource=news letter&q=a",
+			spaceEnc:    PercentTwentyEncoding,
+			keepRaw:     "utm_%zzsource=news%20letter&q=a",
+			encodeStray: "utm_%25zzsource=news%20letter&q=a",
+			wantErr:     true,
+		},
+		{
+			name:        "truncated escape mixed with valid escape",
+			rawQuery:    "q=beras%20p%26g%2&name=%E2%98%95",
+			spaceEnc:    PercentTwentyEncoding,
+			keepRaw:     "q=beras%20p%26g%2&name=%E2%98%95",
+			encodeStray: "q=beras%20p%26g%252&name=%E2%98%95",
+			wantErr:     true,
+		},
+		{
+			name:        "windows-1252 encoded value kept as bytes",
+			rawQuery:    "city=Bogot%E1&q=caf%e9",
+			spaceEnc:    PlusEncoding,
+			keepRaw:     "city=Bogot%E1&q=caf%E9",
+			encodeStray: "city=Bogot%E1&q=caf%E9",
+		},
+		{
+			name:        "non standard %u unicode escape",
+			rawQuery:    "name=caf%u00e9&q=1",
+			spaceEnc:    PlusEncoding,
+			keepRaw:     "name=caf%u00e9&q=1",
+			encodeStray: "name=caf%25u00e9&q=1",
+			wantErr:     true,
+		},
+		{
+			name:        "lone percent and double percent",
+			rawQuery:    "q=%&rate=%%",
+			spaceEnc:    PlusEncoding,
+			keepRaw:     "q=%&rate=%%",
+			encodeStray: "q=%25&rate=%25%25",
+			wantErr:     true,
+		},
+		{
+			name:        "lone percent as key",
+			rawQuery:    "%=1&q=2",
+			spaceEnc:    PlusEncoding,
+			keepRaw:     "%=1&q=2",
+			encodeStray: "%25=1&q=2",
+			wantErr:     true,
+		},
+		{
+			name:        "stray percent followed by space",
+			rawQuery:    "q=50% off",
+			spaceEnc:    PercentTwentyEncoding,
+			keepRaw:     "q=50% off",
+			encodeStray: "q=50%25%20off",
+			wantErr:     true,
+		},
+		{
+			name:        "plus inside key decoded as space",
+			rawQuery:    "sort+by=price+asc&a+b=c",
+			spaceEnc:    PercentTwentyEncoding,
+			keepRaw:     "sort%20by=price%20asc&a%20b=c",
+			encodeStray: "sort%20by=price%20asc&a%20b=c",
+		},
+		{
+			name:        "space kept without default space encoding",
+			rawQuery:    "q=macbook air&b=a+b",
+			keepRaw:     "q=macbook+air&b=a+b",
+			encodeStray: "q=macbook+air&b=a+b",
+		},
+		{
+			name:        "encoded plus kept apart from space",
+			rawQuery:    "q=c%2B%2B+guide",
+			spaceEnc:    PlusEncoding,
+			keepRaw:     "q=c%2B%2B+guide",
+			encodeStray: "q=c%2B%2B+guide",
+		},
+		{
+			name:        "semicolon not a separator",
+			rawQuery:    "a=1;b=2&c=3",
+			spaceEnc:    PlusEncoding,
+			keepRaw:     "a=1%3Bb%3D2&c=3",
+			encodeStray: "a=1%3Bb%3D2&c=3",
+		},
+		{
+			name:        "encoded ampersand, equal sign and hash kept",
+			rawQuery:    "q=a%26b%3Dc&r=x%23y",
+			spaceEnc:    PlusEncoding,
+			keepRaw:     "q=a%26b%3Dc&r=x%23y",
+			encodeStray: "q=a%26b%3Dc&r=x%23y",
+		},
+		{
+			name:        "raw non ASCII and pipe",
+			rawQuery:    "q=kopi☕&tag=a|b",
+			spaceEnc:    PlusEncoding,
+			keepRaw:     "q=kopi%E2%98%95&tag=a%7Cb",
+			encodeStray: "q=kopi%E2%98%95&tag=a%7Cb",
+		},
+		{
+			name:        "raw brackets of array parameters",
+			rawQuery:    "filter[brand]=apple&ids[]=1",
+			spaceEnc:    PlusEncoding,
+			keepRaw:     "filter%5Bbrand%5D=apple&ids%5B%5D=1",
+			encodeStray: "filter%5Bbrand%5D=apple&ids%5B%5D=1",
+		},
+		{
+			name:        "lower case hex upper cased",
+			rawQuery:    "q=%e2%98%95",
+			spaceEnc:    PlusEncoding,
+			keepRaw:     "q=%E2%98%95",
+			encodeStray: "q=%E2%98%95",
+		},
+		{
+			name:        "double encoded value kept",
+			rawQuery:    "next=%252Fcart",
+			spaceEnc:    PlusEncoding,
+			keepRaw:     "next=%252Fcart",
+			encodeStray: "next=%252Fcart",
+		},
+	}
+
+	policies := []struct {
+		name   string
+		policy MalformedPolicy
+	}{
+		{"keep raw", MalformedKeepRaw},
+		{"encode percent", MalformedEncodePercent},
+		{"error", MalformedError},
+	}
+	for _, tt := range testCases {
+		for _, p := range policies {
+			policy := p.policy
+			t.Run(tt.name+"/"+p.name, func(t *testing.T) {
+				ub, err := NewBuilder(Option{
+					URL:                  "https://www.tokopedia.com/search?" + tt.rawQuery,
+					DefaultSpaceEncode:   tt.spaceEnc,
+					UseEscapeAutomateURL: true,
+					MalformedPolicy:      policy,
+				})
+				if policy == MalformedError && tt.wantErr {
+					if !errors.Is(err, ErrorMalformedQuery) {
+						t.Errorf("fail test queryEscapeAutomate() policy %v got error %v want %v", policy, err, ErrorMalformedQuery)
+					}
+					return
+				}
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				want := tt.keepRaw
+				if policy == MalformedEncodePercent {
+					want = tt.encodeStray
+				}
+				if got := ub.GetInternalURL().RawQuery; got != want {
+					t.Errorf("fail test queryEscapeAutomate() policy %v got %v want %v", policy, got, want)
+				}
+			})
+		}
+	}
+}
+
+func Test_SetURLMalformedError(t *testing.T) {
+	ub, err := NewBuilder(Option{
+		URL:                  "https://www.tokopedia.com/search?q=kopi",
+		UseEscapeAutomateURL: true,
+		MalformedPolicy:      MalformedError,
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	err = ub.SetURL("https://www.tokopedia.com/promo?disc=50%")
+	if !errors.Is(err, ErrorMalformedQuery) {
+		t.Errorf("fail test SetURL() got error %v want %v", err, ErrorMalformedQuery)
+	}
+	want := "https://www.tokopedia.com/search?q=kopi"
+	if got := ub.GetURLResult(); got != want {
+		t.Errorf("fail test SetURL() got %v want %v", got, want)
+	}
+}
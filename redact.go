@@ -0,0 +1,150 @@
+package uruki
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// DefaultRedactMask default replacement of redacted value
+const DefaultRedactMask = "REDACTED"
+
+// DefaultRedactQueryKeys default sensitive query keys (glob pattern, case insensitive) used when RedactOptions.QueryKeys is empty
+var DefaultRedactQueryKeys = []string{
+	"*token*",
+	"*secret*",
+	"*password*",
+	"passwd",
+	"pwd",
+	"sig",
+	"signature",
+	"x-amz-signature",
+	"x-amz-credential",
+	"api_key",
+	"apikey",
+	"auth",
+	"authorization",
+}
+
+// RedactOptions options for redacting credential of url before logging
+type RedactOptions struct {
+	// Mask: replacement of redacted value, default DefaultRedactMask
+	Mask string
+	// QueryKeys: sensitive query keys with glob pattern support (see path.Match), case insensitive. default DefaultRedactQueryKeys
+	QueryKeys []string
+	// PathPatterns: path segment that match any of pattern will be redacted
+	PathPatterns []*regexp.Regexp
+}
+
+// RedactedURL redacted view of builder, implement fmt.Stringer, fmt.Formatter and slog.LogValuer
+type RedactedURL struct {
+	ub  *Builder
+	opt RedactOptions
+}
+
+// GetURLResultRedacted get url result with userinfo password, sensitive query values and path segments masked.
+// url structure is kept so the result still parseable
+func (ub *Builder) GetURLResultRedacted(opt RedactOptions) string {
+	if len(opt.Mask) < 1 {
+		opt.Mask = DefaultRedactMask
+	}
+	if len(opt.QueryKeys) < 1 {
+		opt.QueryKeys = DefaultRedactQueryKeys
+	}
+	uri := *ub.url
+	if uri.User != nil {
+		if _, ok := uri.User.Password(); ok {
+			uri.User = url.UserPassword(uri.User.Username(), opt.Mask)
+		}
+	}
+	if len(opt.PathPatterns) > 0 {
+		segments := strings.Split(uri.EscapedPath(), "/")
+		for i, v := range segments {
+			segment, err := url.PathUnescape(v)
+			if err != nil {
+				segment = v
+			}
+			for _, re := range opt.PathPatterns {
+				if len(segment) > 0 && re.MatchString(segment) {
+					segments[i] = url.PathEscape(opt.Mask)
+					break
+				}
+			}
+		}
+		rawPath := strings.Join(segments, "/")
+		if p, err := url.PathUnescape(rawPath); err == nil {
+			uri.Path = p
+			uri.RawPath = rawPath
+		}
+	}
+	uri.RawQuery = redactRawQuery(uri.RawQuery, opt)
+	if strings.Contains(uri.Fragment, "=") {
+		// fragment may carry credential in query form, e.g. oauth implicit flow "#access_token=..."
+		rawFragment := redactRawQuery(uri.EscapedFragment(), opt)
+		if f, err := url.PathUnescape(rawFragment); err == nil {
+			uri.Fragment = f
+			uri.RawFragment = rawFragment
+		}
+	}
+	return uri.String()
+}
+
+// Redacted get redacted view of builder for logging with fmt or slog
+func (ub *Builder) Redacted(opt RedactOptions) RedactedURL {
+	return RedactedURL{ub: ub, opt: opt}
+}
+
+// LogValue implement slog.LogValuer, builder logged with default RedactOptions
+func (ub *Builder) LogValue() slog.Value {
+	return slog.StringValue(ub.GetURLResultRedacted(RedactOptions{}))
+}
+
+// String implement fmt.Stringer
+func (r RedactedURL) String() string {
+	return r.ub.GetURLResultRedacted(r.opt)
+}
+
+// Format implement fmt.Formatter, all verbs formatted against redacted url string
+func (r RedactedURL) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, fmt.FormatString(f, verb), r.String())
+}
+
+// LogValue implement slog.LogValuer
+func (r RedactedURL) LogValue() slog.Value {
+	return slog.StringValue(r.String())
+}
+
+// redactRawQuery mask value of sensitive key on raw query, keep other pairs as is
+func redactRawQuery(rawQuery string, opt RedactOptions) string {
+	if len(rawQuery) < 1 {
+		return rawQuery
+	}
+	keyVal := strings.Split(rawQuery, ampersandStr)
+	for i, queryParam := range keyVal {
+		q, _, hasValue := strings.Cut(queryParam, "=")
+		if !hasValue {
+			continue
+		}
+		key, err := url.QueryUnescape(q)
+		if err != nil {
+			key = q
+		}
+		if matchAnyGlob(strings.ToLower(key), opt.QueryKeys) {
+			keyVal[i] = q + "=" + url.QueryEscape(opt.Mask)
+		}
+	}
+	return strings.Join(keyVal, ampersandStr)
+}
+
+// matchAnyGlob check value match any of glob patterns, case insensitive
+func matchAnyGlob(value string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, err := path.Match(strings.ToLower(p), value); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
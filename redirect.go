@@ -0,0 +1,165 @@
+package uruki
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// RedirectPolicy policy to validate redirect target on SafeRedirect
+type RedirectPolicy struct {
+	// AllowedHosts: hosts allowed other than base host, use "*.tokopedia.com" to allow any subdomain of tokopedia.com
+	AllowedHosts []string
+	// AllowedSchemes: schemes allowed for redirect target, default http, https and scheme of base url
+	AllowedSchemes []string
+}
+
+// specialSchemes schemes that browsers parse with special rules (backslash as slash, authority slashes ignored)
+var specialSchemes = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"ws":    "80",
+	"wss":   "443",
+	"ftp":   "21",
+}
+
+// SafeRedirect resolve target against base and return it as new builder only if it stay same origin with base
+// or on allowed host of policy. target normalized the way browsers do, so `//evil.com`, `/\evil.com`,
+// `https:evil.com` and `java\tscript:` will be validated as browser would navigate
+func SafeRedirect(base *Builder, target string, policy RedirectPolicy) (*Builder, error) {
+	if base == nil || base.url == nil || len(base.url.Host) < 1 {
+		return nil, &Error{Op: "SafeRedirect", Component: "base", Err: ErrorRedirectBase}
+	}
+	rawTarget := target
+	target = normalizeRedirectTarget(target)
+	if len(target) < 1 {
+		return nil, &Error{Op: "SafeRedirect", Component: "target", Input: rawTarget, Err: ErrorRedirectEmpty}
+	}
+
+	allowedSchemes := map[string]bool{strings.ToLower(base.url.Scheme): true}
+	if len(policy.AllowedSchemes) < 1 {
+		allowedSchemes["http"] = true
+		allowedSchemes["https"] = true
+	}
+	for _, v := range policy.AllowedSchemes {
+		allowedSchemes[strings.ToLower(strings.TrimSpace(v))] = true
+	}
+
+	baseScheme := strings.ToLower(base.url.Scheme)
+	if scheme, rest, ok := splitScheme(target); ok {
+		scheme = strings.ToLower(scheme)
+		if !allowedSchemes[scheme] {
+			return nil, &Error{Op: "SafeRedirect", Component: "scheme", Input: scheme, Err: ErrorRedirectScheme}
+		}
+		if _, special := specialSchemes[scheme]; special {
+			if scheme == baseScheme && !strings.HasPrefix(rest, "//") {
+				// same special scheme without authority is relative reference, e.g. "https:evil.com"
+				target = rest
+			} else {
+				// browsers ignore any count of slashes before authority, e.g. "http:evil.com" or "https:///evil.com"
+				target = scheme + "://" + strings.TrimLeft(rest, "/")
+			}
+		}
+	} else if _, special := specialSchemes[baseScheme]; special && strings.HasPrefix(target, "//") {
+		target = "//" + strings.TrimLeft(target, "/")
+	}
+
+	ref, err := url.Parse(target)
+	if err != nil {
+		return nil, withOp("SafeRedirect", rawTarget, err)
+	}
+	resolved := base.url.ResolveReference(ref)
+	if !allowedSchemes[strings.ToLower(resolved.Scheme)] {
+		return nil, &Error{Op: "SafeRedirect", Component: "scheme", Input: resolved.Scheme, Err: ErrorRedirectScheme}
+	}
+	if err := base.checkScheme(resolved.Scheme); err != nil {
+		return nil, withOp("SafeRedirect", target, err)
+	}
+	if resolved.User != nil {
+		return nil, &Error{Op: "SafeRedirect", Component: "userinfo", Input: rawTarget, Err: ErrorRedirectUserinfo}
+	}
+	if !sameOrigin(base.url, resolved) && !hostAllowed(resolved.Hostname(), policy.AllowedHosts) {
+		return nil, &Error{Op: "SafeRedirect", Component: "host", Input: resolved.Host, Err: ErrorRedirectHost}
+	}
+	return base.derive(resolved), nil
+}
+
+// normalizeRedirectTarget strip leading / trailing C0 control and space, remove tab & newline
+// and change backslash into slash like browsers do
+func normalizeRedirectTarget(target string) string {
+	target = strings.TrimFunc(target, func(r rune) bool {
+		return r <= ' '
+	})
+	target = strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return -1
+		}
+		return r
+	}, target)
+	return strings.ReplaceAll(target, `\`, "/")
+}
+
+// splitScheme split scheme and rest of raw url if raw url started with valid scheme
+func splitScheme(rawURL string) (string, string, bool) {
+	for i := 0; i < len(rawURL); i++ {
+		c := rawURL[i]
+		switch {
+		case 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' || c == '+' || c == '-' || c == '.':
+			if i == 0 {
+				return "", rawURL, false
+			}
+		case c == ':':
+			if i == 0 {
+				return "", rawURL, false
+			}
+			return rawURL[:i], rawURL[i+1:], true
+		default:
+			return "", rawURL, false
+		}
+	}
+	return "", rawURL, false
+}
+
+// sameOrigin check scheme, host and port of both url are equal, default port of special scheme included
+func sameOrigin(a, b *url.URL) bool {
+	if !strings.EqualFold(a.Scheme, b.Scheme) {
+		return false
+	}
+	if !strings.EqualFold(a.Hostname(), b.Hostname()) {
+		return false
+	}
+	return effectivePort(a) == effectivePort(b)
+}
+
+// effectivePort port of url, fallback to default port of special scheme
+func effectivePort(uri *url.URL) string {
+	if port := uri.Port(); port != "" {
+		return port
+	}
+	return specialSchemes[strings.ToLower(uri.Scheme)]
+}
+
+// hostAllowed check host match any of allowed host, support "*." prefix for subdomains
+func hostAllowed(host string, allowedHosts []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if len(host) < 1 {
+		return false
+	}
+	for _, v := range allowedHosts {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if h, _, err := net.SplitHostPort(v); err == nil {
+			v = h
+		}
+		if strings.HasPrefix(v, "*.") {
+			if strings.HasSuffix(host, v[1:]) {
+				return true
+			}
+			continue
+		}
+		if host == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package uruki
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_SafeRedirect(t *testing.T) {
+	type args struct {
+		name    string
+		target  string
+		policy  RedirectPolicy
+		wantURL string
+		wantErr error
+	}
+
+	testCases := []args{
+		{
+			name:    "relative path same origin",
+			target:  "/cart?from=login",
+			wantURL: "https://www.tokopedia.com/cart?from=login",
+		},
+		{
+			name:    "absolute same origin with default port",
+			target:  "https://www.tokopedia.com:443/order",
+			wantURL: "https://www.tokopedia.com:443/order",
+		},
+		{
+			name:    "scheme relative evil host",
+			target:  "//evil.com",
+			wantErr: ErrorRedirectHost,
+		},
+		{
+			name:    "backslash evil host",
+			target:  `/\evil.com`,
+			wantErr: ErrorRedirectHost,
+		},
+		{
+			name:    "triple slash evil host",
+			target:  "///evil.com/path",
+			wantErr: ErrorRedirectHost,
+		},
+		{
+			name:    "same scheme without slashes is relative",
+			target:  "https:evil.com",
+			wantURL: "https://www.tokopedia.com/evil.com",
+		},
+		{
+			name:    "other special scheme without slashes is authority",
+			target:  "http:evil.com",
+			wantErr: ErrorRedirectHost,
+		},
+		{
+			name:    "javascript scheme",
+			target:  "javascript:alert(1)",
+			wantErr: ErrorRedirectScheme,
+		},
+		{
+			name:    "javascript scheme obfuscated with tab and case",
+			target:  " JaVa\tScRipt:alert(1)",
+			wantErr: ErrorRedirectScheme,
+		},
+		{
+			name:    "newline inside host",
+			target:  "//ev\nil.com",
+			wantErr: ErrorRedirectHost,
+		},
+		{
+			name:    "userinfo trick",
+			target:  "https://www.tokopedia.com@evil.com",
+			wantErr: ErrorRedirectUserinfo,
+		},
+		{
+			name:    "allowlisted host",
+			target:  "https://m.tokopedia.com/discovery",
+			policy:  RedirectPolicy{AllowedHosts: []string{"m.tokopedia.com"}},
+			wantURL: "https://m.tokopedia.com/discovery",
+		},
+		{
+			name:    "allowlisted subdomain wildcard",
+			target:  "https://seller.tokopedia.com/home",
+			policy:  RedirectPolicy{AllowedHosts: []string{"*.tokopedia.com"}},
+			wantURL: "https://seller.tokopedia.com/home",
+		},
+		{
+			name:    "wildcard not match suffix trick",
+			target:  "https://eviltokopedia.com/home",
+			policy:  RedirectPolicy{AllowedHosts: []string{"*.tokopedia.com"}},
+			wantErr: ErrorRedirectHost,
+		},
+		{
+			name:    "scheme not in policy",
+			target:  "tokopedia://home",
+			wantErr: ErrorRedirectScheme,
+		},
+		{
+			name:    "empty target",
+			target:  " \t ",
+			wantErr: ErrorRedirectEmpty,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			base, err := NewBuilder(Option{
+				URL: "https://www.tokopedia.com/login?next=x",
+			})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			got, err := SafeRedirect(base, tt.target, tt.policy)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("fail error test SafeRedirect() got %v want %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.GetURLResult() != tt.wantURL {
+				t.Errorf("fail value test SafeRedirect() got %v want %v", got.GetURLResult(), tt.wantURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package uruki
+
+import (
+	"html"
+	"net/url"
+	"strings"
+)
+
+// DefaultSanitizeFallback fallback url returned by SanitizeHref when link is not safe
+const DefaultSanitizeFallback = "about:blank"
+
+// SanitizeOptions options for sanitize user supplied link
+type SanitizeOptions struct {
+	// AllowedDataMIMETypes: mime types allowed on data: url, example []string{"image/png", "image/jpeg"}. default all data: url is blocked.
+	// avoid "image/svg+xml" since it can contains script
+	AllowedDataMIMETypes []string
+	// BlockedSchemes: additional schemes to block other than javascript, vbscript and data
+	BlockedSchemes []string
+	// Fallback: url returned when link is not safe, default DefaultSanitizeFallback
+	Fallback string
+}
+
+// dangerousSchemes schemes blocked by default on SanitizeHref
+var dangerousSchemes = []string{"javascript", "vbscript", "data"}
+
+// SanitizeHref sanitize user supplied link before rendering it as href. javascript:, vbscript: and data: (except allowed mime types)
+// are stripped even when obfuscated with tab / newline / control characters, entity or percent encoding and mixed case.
+// return trimmed raw and true when safe, otherwise fallback url and false
+func SanitizeHref(raw string, opt SanitizeOptions) (string, bool) {
+	fallback := opt.Fallback
+	if len(fallback) < 1 {
+		fallback = DefaultSanitizeFallback
+	}
+	scheme, rest, ok := splitScheme(revealHref(raw))
+	if !ok {
+		return strings.TrimSpace(raw), true
+	}
+	scheme = strings.ToLower(scheme)
+	blocked := false
+	for _, v := range append(dangerousSchemes, opt.BlockedSchemes...) {
+		if scheme == strings.ToLower(strings.TrimSpace(v)) {
+			blocked = true
+			break
+		}
+	}
+	if !blocked {
+		return strings.TrimSpace(raw), true
+	}
+	if scheme == "data" && dataMIMEAllowed(rest, opt.AllowedDataMIMETypes) {
+		return strings.TrimSpace(raw), true
+	}
+	return fallback, false
+}
+
+// revealHref decode entity & percent encoding repeatedly and remove whitespace & control characters,
+// so obfuscated scheme is visible
+func revealHref(raw string) string {
+	for i := 0; i < 5; i++ {
+		decoded := html.UnescapeString(raw)
+		if unescaped, err := url.PathUnescape(decoded); err == nil {
+			decoded = unescaped
+		}
+		decoded = strings.Map(func(r rune) rune {
+			if r <= ' ' || r == 0x7f || r == 0xad || r == 0x200b || r == 0xfeff {
+				return -1
+			}
+			return r
+		}, decoded)
+		if decoded == raw {
+			break
+		}
+		raw = decoded
+	}
+	return raw
+}
+
+// dataMIMEAllowed check mime type of data url body (after "data:") is in allowed mime types
+func dataMIMEAllowed(body string, allowed []string) bool {
+	end := strings.IndexAny(body, ";,")
+	if end < 0 {
+		return false
+	}
+	mime := strings.ToLower(body[:end])
+	for _, v := range allowed {
+		if mime == strings.ToLower(strings.TrimSpace(v)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package uruki
+
+import "testing"
+
+func Test_SanitizeHref(t *testing.T) {
+	type args struct {
+		name     string
+		raw      string
+		opt      SanitizeOptions
+		wantHref string
+		wantOk   bool
+	}
+
+	testCases := []args{
+		{
+			name:     "safe https link",
+			raw:      " https://www.tokopedia.com/discovery?q=javascript:alert ",
+			wantHref: "https://www.tokopedia.com/discovery?q=javascript:alert",
+			wantOk:   true,
+		},
+		{
+			name:     "safe relative link",
+			raw:      "/help/article-1",
+			wantHref: "/help/article-1",
+			wantOk:   true,
+		},
+		{
+			name:     "javascript scheme",
+			raw:      "javascript:alert(1)",
+			wantHref: DefaultSanitizeFallback,
+		},
+		{
+			name:     "mixed case with tab and newline",
+			raw:      "JaV\tasC\nRipt:alert(1)",
+			wantHref: DefaultSanitizeFallback,
+		},
+		{
+			name:     "leading control characters",
+			raw:      "\x01\x02 javascript:alert(1)",
+			wantHref: DefaultSanitizeFallback,
+		},
+		{
+			name:     "numeric entity encoded",
+			raw:      "&#106;&#x61;vascript&#58;alert(1)",
+			wantHref: DefaultSanitizeFallback,
+		},
+		{
+			name:     "named entity colon and double encoding",
+			raw:      "javascript&amp;colon;alert(1)",
+			wantHref: DefaultSanitizeFallback,
+		},
+		{
+			name:     "percent encoded scheme",
+			raw:      "%6Aavascript:alert(1)",
+			wantHref: DefaultSanitizeFallback,
+		},
+		{
+			name:     "vbscript",
+			raw:      "VBScript:msgbox(1)",
+			wantHref: DefaultSanitizeFallback,
+		},
+		{
+			name:     "data html blocked",
+			raw:      "data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==",
+			opt:      SanitizeOptions{AllowedDataMIMETypes: []string{"image/png"}},
+			wantHref: DefaultSanitizeFallback,
+		},
+		{
+			name:     "data image allowed",
+			raw:      "data:image/png;base64,iVBORw0KGgo=",
+			opt:      SanitizeOptions{AllowedDataMIMETypes: []string{"image/png"}},
+			wantHref: "data:image/png;base64,iVBORw0KGgo=",
+			wantOk:   true,
+		},
+		{
+			name:     "custom blocked scheme and fallback",
+			raw:      "file:///etc/passwd",
+			opt:      SanitizeOptions{BlockedSchemes: []string{"file"}, Fallback: "#"},
+			wantHref: "#",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SanitizeHref(tt.raw, tt.opt)
+			if got != tt.wantHref || ok != tt.wantOk {
+				t.Errorf("fail test SanitizeHref() got %v %v want %v %v", got, ok, tt.wantHref, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package uruki
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+//go:generate go test -run Test_ComponentsJSONSchema -update
+
+// ComponentsJSONSchema JSON Schema (draft 2020-12) of Components generated from its fields, the same as components.schema.json
+func ComponentsJSONSchema() ([]byte, error) {
+	schema := jsonSchemaOf(reflect.TypeOf(Components{}))
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "Components"
+	schema["description"] = "structured form of url, see uruki.Components"
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// jsonSchemaOf JSON Schema of type, struct described by json and jsonschema_description tags of fields
+func jsonSchemaOf(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return jsonSchemaOf(t.Elem())
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": jsonSchemaOf(t.Elem())}
+	case reflect.Struct:
+		properties := map[string]any{}
+		required := make([]string, 0)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if len(name) < 1 {
+				name = field.Name
+			}
+			property := jsonSchemaOf(field.Type)
+			if description := field.Tag.Get("jsonschema_description"); len(description) > 0 {
+				property["description"] = description
+			}
+			properties[name] = property
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+		schema := map[string]any{"type": "object", "properties": properties, "additionalProperties": false}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	}
+	return map[string]any{"type": "string"}
+}
@@ -0,0 +1,248 @@
+package uruki
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"hash"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignAlgorithm hmac algorithm of signed url
+type SignAlgorithm string
+
+// constants of SignAlgorithm
+const (
+	SignHMACSHA256 SignAlgorithm = "HS256" // HMAC with SHA-256, default
+	SignHMACSHA512 SignAlgorithm = "HS512" // HMAC with SHA-512
+)
+
+// SignComponent url components covered by signature, combine with bitwise or
+type SignComponent uint8
+
+// constants of SignComponent
+const (
+	SignScheme SignComponent = 1 << iota
+	SignHost
+	SignPath
+	SignQuery
+	SignFragment
+
+	// SignDefaultComponents default signed components, all except fragment since it is not sent to server
+	SignDefaultComponents = SignScheme | SignHost | SignPath | SignQuery
+)
+
+// default query parameter names of signed url
+const (
+	DefaultSignatureParam = "signature"
+	DefaultExpiresParam   = "expires"
+	DefaultKeyIDParam     = "kid"
+)
+
+// SignOptions options for Sign
+type SignOptions struct {
+	// Key: hmac secret key, required
+	Key []byte
+	// KeyID: optional key identifier appended as KeyIDParamName, used by Verify to pick key on rotation
+	KeyID string
+	// Algorithm: hmac algorithm, default SignHMACSHA256
+	Algorithm SignAlgorithm
+	// ExpiresAt: expiry time appended as unix seconds on ExpiresParamName, zero value mean never expire
+	ExpiresAt time.Time
+	// ParamName: query parameter name of signature, default DefaultSignatureParam
+	ParamName string
+	// ExpiresParamName: query parameter name of expiry, default DefaultExpiresParam
+	ExpiresParamName string
+	// KeyIDParamName: query parameter name of key id, default DefaultKeyIDParam
+	KeyIDParamName string
+	// SignedComponents: url components covered by signature, default SignDefaultComponents
+	SignedComponents SignComponent
+}
+
+// VerifyOptions options for Verify, param names, algorithm and signed components must be same as SignOptions
+type VerifyOptions struct {
+	// Keys: hmac secret keys by key id, use "" key for url signed without KeyID
+	Keys map[string][]byte
+	// Algorithm: hmac algorithm, default SignHMACSHA256
+	Algorithm SignAlgorithm
+	// ParamName: query parameter name of signature, default DefaultSignatureParam
+	ParamName string
+	// ExpiresParamName: query parameter name of expiry, default DefaultExpiresParam
+	ExpiresParamName string
+	// KeyIDParamName: query parameter name of key id, default DefaultKeyIDParam
+	KeyIDParamName string
+	// SignedComponents: url components covered by signature, default SignDefaultComponents
+	SignedComponents SignComponent
+	// Now: current time used to check expiry, default time.Now
+	Now func() time.Time
+}
+
+// Sign compute hmac signature over canonical form of signed components and append expiry, key id and signature
+// as query parameters. existing signature parameters are replaced
+func (ub *Builder) Sign(opt SignOptions) error {
+	return ub.mutate("Sign", []string{opt.KeyID}, func() error {
+		if len(opt.Key) < 1 {
+			return &Error{Op: "Sign", Component: "key", Input: opt.KeyID, Err: ErrorSignKeyEmpty}
+		}
+		params := signParams{opt.ParamName, opt.ExpiresParamName, opt.KeyIDParamName}.withDefault()
+		newHash, err := signHash(opt.Algorithm)
+		if err != nil {
+			return withOp("Sign", "", err)
+		}
+		ub.deleteRawQueryKeys(params.signature, params.expires, params.keyID)
+		if !opt.ExpiresAt.IsZero() {
+			ub.appendRawQuery(url.QueryEscape(params.expires) + "=" + strconv.FormatInt(opt.ExpiresAt.Unix(), 10))
+		}
+		if len(opt.KeyID) > 0 {
+			ub.appendRawQuery(url.QueryEscape(params.keyID) + "=" + url.QueryEscape(opt.KeyID))
+		}
+		signature := computeSignature(newHash, opt.Key, canonicalSignedURL(ub.url, params, opt.SignedComponents))
+		ub.appendRawQuery(url.QueryEscape(params.signature) + "=" + signature)
+		return nil
+	})
+}
+
+// Verify check signature of signed url. return ErrorSignatureMissing, ErrorSignatureKeyUnknown, ErrorSignatureInvalid
+// when tampered or signature, expiry or key id parameter repeated, or ErrorSignatureExpired
+func Verify(raw string, opt VerifyOptions) error {
+	uri, err := url.Parse(raw)
+	if err != nil {
+		return withOp("Verify", raw, err)
+	}
+	params := signParams{opt.ParamName, opt.ExpiresParamName, opt.KeyIDParamName}.withDefault()
+	newHash, err := signHash(opt.Algorithm)
+	if err != nil {
+		return withOp("Verify", "", err)
+	}
+	query := parseRawQueryPairs(uri.RawQuery)
+	if name, ok := repeatedSignParam(query, params); ok {
+		return &Error{Op: "Verify", Component: "query", Input: name, Err: ErrorSignatureInvalid}
+	}
+	signature, ok := lookupQueryPair(query, params.signature)
+	if !ok || len(signature) < 1 {
+		return &Error{Op: "Verify", Component: "query", Input: params.signature, Err: ErrorSignatureMissing}
+	}
+	keyID, _ := lookupQueryPair(query, params.keyID)
+	key, ok := opt.Keys[keyID]
+	if !ok || len(key) < 1 {
+		return &Error{Op: "Verify", Component: "key", Input: keyID, Err: ErrorSignatureKeyUnknown}
+	}
+	expected := computeSignature(newHash, key, canonicalSignedURL(uri, params, opt.SignedComponents))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return &Error{Op: "Verify", Component: "signature", Input: signature, Err: ErrorSignatureInvalid}
+	}
+	if expires, ok := lookupQueryPair(query, params.expires); ok {
+		unix, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil {
+			return &Error{Op: "Verify", Component: "expires", Input: expires, Err: ErrorSignatureInvalid}
+		}
+		now := time.Now
+		if opt.Now != nil {
+			now = opt.Now
+		}
+		if !now().Before(time.Unix(unix, 0)) {
+			return &Error{Op: "Verify", Component: "expires", Input: expires, Err: ErrorSignatureExpired}
+		}
+	}
+	return nil
+}
+
+// signParams query parameter names of signed url
+type signParams struct {
+	signature string
+	expires   string
+	keyID     string
+}
+
+// withDefault fill empty parameter names with default names
+func (p signParams) withDefault() signParams {
+	if len(p.signature) < 1 {
+		p.signature = DefaultSignatureParam
+	}
+	if len(p.expires) < 1 {
+		p.expires = DefaultExpiresParam
+	}
+	if len(p.keyID) < 1 {
+		p.keyID = DefaultKeyIDParam
+	}
+	return p
+}
+
+// repeatedSignParam name of signature, expiry or key id parameter found more than once in query
+func repeatedSignParam(query []queryPair, params signParams) (string, bool) {
+	count := map[string]int{}
+	for _, v := range query {
+		switch v.key {
+		case params.signature, params.expires, params.keyID:
+			count[v.key]++
+			if count[v.key] > 1 {
+				return v.key, true
+			}
+		}
+	}
+	return "", false
+}
+
+// signHash hash constructor of algorithm
+func signHash(algorithm SignAlgorithm) (func() hash.Hash, error) {
+	switch algorithm {
+	case "", SignHMACSHA256:
+		return sha256.New, nil
+	case SignHMACSHA512:
+		return sha512.New, nil
+	}
+	return nil, &Error{Component: "algorithm", Input: string(algorithm), Err: ErrorSignAlgorithm}
+}
+
+// computeSignature hmac of canonical form encoded as unpadded base64url
+func computeSignature(newHash func() hash.Hash, key []byte, canonical string) string {
+	mac := hmac.New(newHash, key)
+	mac.Write([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// canonicalSignedURL canonical form of url for signing. expiry and key id always covered, query sorted and re-encoded
+// so equivalent encoding produce same signature
+func canonicalSignedURL(uri *url.URL, params signParams, components SignComponent) string {
+	if components == 0 {
+		components = SignDefaultComponents
+	}
+	query := parseRawQueryPairs(uri.RawQuery)
+	expires, _ := lookupQueryPair(query, params.expires)
+	keyID, _ := lookupQueryPair(query, params.keyID)
+	lines := []string{
+		"v1",
+		strconv.Itoa(int(components)),
+		params.expires + ":" + expires,
+		params.keyID + ":" + keyID,
+	}
+	if components&SignScheme != 0 {
+		lines = append(lines, strings.ToLower(uri.Scheme))
+	}
+	if components&SignHost != 0 {
+		lines = append(lines, strings.ToLower(uri.Host))
+	}
+	if components&SignPath != 0 {
+		lines = append(lines, uri.EscapedPath())
+	}
+	if components&SignQuery != 0 {
+		canonicalQuery := make([]string, 0, len(query))
+		for _, v := range query {
+			if v.key == params.signature {
+				continue
+			}
+			canonicalQuery = append(canonicalQuery, url.QueryEscape(v.key)+"="+url.QueryEscape(v.val))
+		}
+		sort.Strings(canonicalQuery)
+		lines = append(lines, strings.Join(canonicalQuery, ampersandStr))
+	}
+	if components&SignFragment != 0 {
+		lines = append(lines, uri.Fragment)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,204 @@
+package uruki
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_SignVerify(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	keys := map[string][]byte{
+		"":   []byte("legacy-secret"),
+		"k1": []byte("old-secret"),
+		"k2": []byte("new-secret"),
+	}
+	type args struct {
+		name    string
+		signOpt SignOptions
+		tamper  func(signed string) string
+		verify  VerifyOptions
+		wantErr error
+	}
+
+	testCases := []args{
+		{
+			name:    "valid without expiry and key id",
+			signOpt: SignOptions{Key: keys[""]},
+			verify:  VerifyOptions{Keys: keys},
+		},
+		{
+			name:    "valid with expiry and rotated key",
+			signOpt: SignOptions{Key: keys["k2"], KeyID: "k2", ExpiresAt: now.Add(time.Hour)},
+			verify:  VerifyOptions{Keys: keys, Now: func() time.Time { return now }},
+		},
+		{
+			name:    "old key still verified after rotation",
+			signOpt: SignOptions{Key: keys["k1"], KeyID: "k1", Algorithm: SignHMACSHA512},
+			verify:  VerifyOptions{Keys: keys, Algorithm: SignHMACSHA512},
+		},
+		{
+			name:    "expired",
+			signOpt: SignOptions{Key: keys["k2"], KeyID: "k2", ExpiresAt: now.Add(-time.Second)},
+			verify:  VerifyOptions{Keys: keys, Now: func() time.Time { return now }},
+			wantErr: ErrorSignatureExpired,
+		},
+		{
+			name:    "tampered expiry",
+			signOpt: SignOptions{Key: keys["k2"], KeyID: "k2", ExpiresAt: now.Add(-time.Second)},
+			tamper: func(signed string) string {
+				return strings.Replace(signed, "expires=", "expires=9", 1)
+			},
+			verify:  VerifyOptions{Keys: keys, Now: func() time.Time { return now }},
+			wantErr: ErrorSignatureInvalid,
+		},
+		{
+			name:    "tampered query",
+			signOpt: SignOptions{Key: keys[""]},
+			tamper: func(signed string) string {
+				return strings.Replace(signed, "file=report.pdf", "file=payroll.pdf", 1)
+			},
+			verify:  VerifyOptions{Keys: keys},
+			wantErr: ErrorSignatureInvalid,
+		},
+		{
+			name:    "tampered host",
+			signOpt: SignOptions{Key: keys[""]},
+			tamper: func(signed string) string {
+				return strings.Replace(signed, "www.tokopedia.com", "evil.com", 1)
+			},
+			verify:  VerifyOptions{Keys: keys},
+			wantErr: ErrorSignatureInvalid,
+		},
+		{
+			name:    "host not signed",
+			signOpt: SignOptions{Key: keys[""], SignedComponents: SignPath | SignQuery},
+			tamper: func(signed string) string {
+				return strings.Replace(signed, "www.tokopedia.com", "m.tokopedia.com", 1)
+			},
+			verify: VerifyOptions{Keys: keys, SignedComponents: SignPath | SignQuery},
+		},
+		{
+			name:    "query reordered and re-encoded still valid",
+			signOpt: SignOptions{Key: keys[""], ParamName: "sig"},
+			tamper: func(signed string) string {
+				return strings.Replace(signed, "?file=report.pdf&user=budi+santoso", "?user=budi%20santoso&file=report.pdf", 1)
+			},
+			verify: VerifyOptions{Keys: keys, ParamName: "sig"},
+		},
+		{
+			name:    "repeated signature",
+			signOpt: SignOptions{Key: keys[""]},
+			tamper: func(signed string) string {
+				return signed + "&signature=anything"
+			},
+			verify:  VerifyOptions{Keys: keys},
+			wantErr: ErrorSignatureInvalid,
+		},
+		{
+			name:    "repeated expiry",
+			signOpt: SignOptions{Key: keys["k2"], KeyID: "k2", ExpiresAt: now.Add(-time.Second)},
+			tamper: func(signed string) string {
+				return signed + "&expires=9999999999"
+			},
+			verify:  VerifyOptions{Keys: keys, Now: func() time.Time { return now }},
+			wantErr: ErrorSignatureInvalid,
+		},
+		{
+			name:    "repeated key id",
+			signOpt: SignOptions{Key: keys["k2"], KeyID: "k2"},
+			tamper: func(signed string) string {
+				return signed + "&kid=k1"
+			},
+			verify:  VerifyOptions{Keys: keys},
+			wantErr: ErrorSignatureInvalid,
+		},
+		{
+			name:    "missing signature",
+			signOpt: SignOptions{Key: keys[""]},
+			tamper: func(signed string) string {
+				return signed[:strings.Index(signed, "&signature=")]
+			},
+			verify:  VerifyOptions{Keys: keys},
+			wantErr: ErrorSignatureMissing,
+		},
+		{
+			name:    "unknown key id",
+			signOpt: SignOptions{Key: []byte("x"), KeyID: "k9"},
+			verify:  VerifyOptions{Keys: keys},
+			wantErr: ErrorSignatureKeyUnknown,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ub, err := NewBuilder(Option{URL: "https://www.tokopedia.com/download?file=report.pdf&user=budi+santoso"})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			err = ub.Sign(tt.signOpt)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			signed := ub.GetURLResult()
+			if tt.tamper != nil {
+				signed = tt.tamper(signed)
+			}
+			err = Verify(signed, tt.verify)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("fail test Verify() %v got %v want %v", signed, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_SignReplaceExisting(t *testing.T) {
+	ub, err := NewBuilder(Option{URL: "https://www.tokopedia.com/unsubscribe?uid=1"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := ub.Sign(SignOptions{}); !errors.Is(err, ErrorSignKeyEmpty) {
+		t.Errorf("fail test Sign() got %v want %v", err, ErrorSignKeyEmpty)
+	}
+	opt := SignOptions{Key: []byte("secret"), KeyID: "k1", ExpiresAt: time.Unix(1700000000, 0)}
+	if err := ub.Sign(opt); err != nil {
+		t.Error(err)
+		return
+	}
+	first := ub.GetURLResult()
+	if err := ub.Sign(opt); err != nil {
+		t.Error(err)
+		return
+	}
+	if got := ub.GetURLResult(); got != first {
+		t.Errorf("fail test Sign() re-sign got %v want %v", got, first)
+	}
+	if !strings.HasPrefix(first, "https://www.tokopedia.com/unsubscribe?uid=1&expires=1700000000&kid=k1&signature=") {
+		t.Errorf("fail test Sign() got %v", first)
+	}
+}
+
+func Test_SignKeepQueryValue(t *testing.T) {
+	rawURL := "https://www.tokopedia.com/download?data=a=b&token=YWJj==&debug&file=x&signature=old"
+	ub, err := NewBuilder(Option{URL: rawURL})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := ub.Sign(SignOptions{Key: []byte("secret")}); err != nil {
+		t.Error(err)
+		return
+	}
+	signed := ub.GetURLResult()
+	want := "https://www.tokopedia.com/download?data=a=b&token=YWJj==&debug&file=x&signature="
+	if !strings.HasPrefix(signed, want) {
+		t.Errorf("fail test Sign() got %v want prefix %v", signed, want)
+	}
+	if err := Verify(signed, VerifyOptions{Keys: map[string][]byte{"": []byte("secret")}}); err != nil {
+		t.Errorf("fail test Verify() %v got %v want %v", signed, err, nil)
+	}
+}
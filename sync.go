@@ -0,0 +1,350 @@
+package uruki
+
+import (
+	"net/url"
+	"sync"
+)
+
+// SyncBuilder concurrency safe Builder, all getters and setters guarded by RW lock.
+// use Update for atomic read-modify-write and Clone to take per request copy of template
+type SyncBuilder struct {
+	mu sync.RWMutex
+	ub *Builder
+}
+
+// NewSyncBuilder create concurrency safe builder, see NewBuilder
+func NewSyncBuilder(options ...Option) (*SyncBuilder, error) {
+	ub, err := NewBuilder(options...)
+	if err != nil {
+		return nil, err
+	}
+	return &SyncBuilder{ub: ub}, nil
+}
+
+// Synchronized wrap builder as concurrency safe builder, builder must not be used directly afterward
+func Synchronized(ub *Builder) *SyncBuilder {
+	return &SyncBuilder{ub: ub}
+}
+
+// Update atomic read-modify-write, fn run on copy of builder under write lock and applied only when fn return nil.
+// hooks can veto changes of fn, AfterChange of hooks called only when the update applied
+func (sb *SyncBuilder) Update(fn func(ub *Builder) error) error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	clone := sb.ub.Clone()
+	hooks := clone.hooks
+	deferred := &deferredHook{hooks: hooks}
+	if len(hooks) > 0 {
+		clone.hooks = []Hook{deferred}
+	}
+	if err := fn(clone); err != nil {
+		return err
+	}
+	clone.hooks = hooks
+	sb.ub = clone
+	for _, event := range deferred.events {
+		clone.afterChange(event)
+	}
+	return nil
+}
+
+// deferredHook call BeforeChange of hooks and hold AfterChange events until Update applied
+type deferredHook struct {
+	hooks  []Hook
+	events []ChangeEvent
+}
+
+// BeforeChange see Hook
+func (h *deferredHook) BeforeChange(event ChangeEvent) error {
+	for _, hook := range h.hooks {
+		if err := hook.BeforeChange(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AfterChange see Hook
+func (h *deferredHook) AfterChange(event ChangeEvent) {
+	h.events = append(h.events, event)
+}
+
+// View read builder under read lock, fn must not change the builder
+func (sb *SyncBuilder) View(fn func(ub *Builder) error) error {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return fn(sb.ub)
+}
+
+// Clone deep copy of underlying builder, not synchronized
+func (sb *SyncBuilder) Clone() *Builder {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.ub.Clone()
+}
+
+// URL immutable snapshot of url
+func (sb *SyncBuilder) URL() URL {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.ub.URL()
+}
+
+// GetURLResultUnescape see Builder.GetURLResultUnescape
+func (sb *SyncBuilder) GetURLResultUnescape() string {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.ub.GetURLResultUnescape()
+}
+
+// GetURLResult see Builder.GetURLResult
+func (sb *SyncBuilder) GetURLResult() string {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.ub.GetURLResult()
+}
+
+// GetValueQuery see Builder.GetValueQuery
+func (sb *SyncBuilder) GetValueQuery(key string) string {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.ub.GetValueQuery(key)
+}
+
+// GetAllQueryValue see Builder.GetAllQueryValue
+func (sb *SyncBuilder) GetAllQueryValue() map[string][]string {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.ub.GetAllQueryValue()
+}
+
+// GetInternalURL see Builder.GetInternalURL
+func (sb *SyncBuilder) GetInternalURL() url.URL {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.ub.GetInternalURL()
+}
+
+// GetPaths see Builder.GetPaths
+func (sb *SyncBuilder) GetPaths() []string {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.ub.GetPaths()
+}
+
+// GetFullPath see Builder.GetFullPath
+func (sb *SyncBuilder) GetFullPath() string {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.ub.GetFullPath()
+}
+
+// ToComponents see Builder.ToComponents
+func (sb *SyncBuilder) ToComponents() Components {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.ub.ToComponents()
+}
+
+// SetBaseURL see Builder.SetBaseURL
+func (sb *SyncBuilder) SetBaseURL(baseURL string) error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.ub.SetBaseURL(baseURL)
+}
+
+// SetPath see Builder.SetPath
+func (sb *SyncBuilder) SetPath(path string) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	sb.ub.SetPath(path)
+}
+
+// SetURL see Builder.SetURL
+func (sb *SyncBuilder) SetURL(uri string) error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.ub.SetURL(uri)
+}
+
+// AddQueryParam see Builder.AddQueryParam
+func (sb *SyncBuilder) AddQueryParam(opt AddQueryParamOpt) error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.ub.AddQueryParam(opt)
+}
+
+// DeleteFragment see Builder.DeleteFragment
+func (sb *SyncBuilder) DeleteFragment() {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	sb.ub.DeleteFragment()
+}
+
+// SetFragment see Builder.SetFragment
+func (sb *SyncBuilder) SetFragment(opt SetFragmentOpt) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	sb.ub.SetFragment(opt)
+}
+
+// SetPathSegments see Builder.SetPathSegments
+func (sb *SyncBuilder) SetPathSegments(opt SetPathSegmentsOpt) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	sb.ub.SetPathSegments(opt)
+}
+
+// DeleteKeyQuery see Builder.DeleteKeyQuery
+func (sb *SyncBuilder) DeleteKeyQuery(keyDelete string) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	sb.ub.DeleteKeyQuery(keyDelete)
+}
+
+// History see Builder.History
+func (sb *SyncBuilder) History() []HistoryEntry {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.ub.History()
+}
+
+// Undo see Builder.Undo
+func (sb *SyncBuilder) Undo() error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.ub.Undo()
+}
+
+// Redo see Builder.Redo
+func (sb *SyncBuilder) Redo() error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.ub.Redo()
+}
+
+// Snapshot see Builder.Snapshot
+func (sb *SyncBuilder) Snapshot() SnapshotID {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.ub.Snapshot()
+}
+
+// Restore see Builder.Restore
+func (sb *SyncBuilder) Restore(id SnapshotID) error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.ub.Restore(id)
+}
+
+// GetURLResultRedacted see Builder.GetURLResultRedacted
+func (sb *SyncBuilder) GetURLResultRedacted(opt RedactOptions) string {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.ub.GetURLResultRedacted(opt)
+}
+
+// Redacted see Builder.Redacted, view made of snapshot of url so it can be formatted without lock
+func (sb *SyncBuilder) Redacted(opt RedactOptions) RedactedURL {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.ub.Clone().Redacted(opt)
+}
+
+// StripTrackingParams see Builder.StripTrackingParams
+func (sb *SyncBuilder) StripTrackingParams(profiles ...StripProfile) []StrippedParam {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.ub.StripTrackingParams(profiles...)
+}
+
+// GetCampaign see Builder.GetCampaign
+func (sb *SyncBuilder) GetCampaign() Campaign {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.ub.GetCampaign()
+}
+
+// SetCampaign see Builder.SetCampaign
+func (sb *SyncBuilder) SetCampaign(campaign Campaign, opt CampaignOptions) error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.ub.SetCampaign(campaign, opt)
+}
+
+// Sign see Builder.Sign
+func (sb *SyncBuilder) Sign(opt SignOptions) error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.ub.Sign(opt)
+}
+
+// PresignV4 see Builder.PresignV4
+func (sb *SyncBuilder) PresignV4(opt PresignV4Options) error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.ub.PresignV4(opt)
+}
+
+// NestedQuery see Builder.NestedQuery
+func (sb *SyncBuilder) NestedQuery(key string) (*Builder, error) {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.ub.NestedQuery(key)
+}
+
+// SetNestedQuery see Builder.SetNestedQuery
+func (sb *SyncBuilder) SetNestedQuery(key string, child *Builder) error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.ub.SetNestedQuery(key, child)
+}
+
+// FragmentRoute see Builder.FragmentRoute
+func (sb *SyncBuilder) FragmentRoute() (*Builder, error) {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.ub.FragmentRoute()
+}
+
+// SetFragmentRoute see Builder.SetFragmentRoute
+func (sb *SyncBuilder) SetFragmentRoute(child *Builder) error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.ub.SetFragmentRoute(child)
+}
+
+// GetTextFragment see Builder.GetTextFragment
+func (sb *SyncBuilder) GetTextFragment() ([]TextDirective, error) {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.ub.GetTextFragment()
+}
+
+// GetFragmentElementID see Builder.GetFragmentElementID
+func (sb *SyncBuilder) GetFragmentElementID() string {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.ub.GetFragmentElementID()
+}
+
+// SetTextFragment see Builder.SetTextFragment
+func (sb *SyncBuilder) SetTextFragment(directives ...TextDirective) error {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.ub.SetTextFragment(directives...)
+}
+
+// EncodingReport see Builder.EncodingReport
+func (sb *SyncBuilder) EncodingReport() []EncodingIssue {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	return sb.ub.EncodingReport()
+}
+
+// RepairEncoding see Builder.RepairEncoding
+func (sb *SyncBuilder) RepairEncoding(opt RepairOptions) []EncodingIssue {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.ub.RepairEncoding(opt)
+}
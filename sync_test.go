@@ -0,0 +1,168 @@
+package uruki
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func Test_SyncBuilderConcurrent(t *testing.T) {
+	sb, err := NewSyncBuilder(Option{URL: "https://www.tokopedia.com/search", DefaultSpaceEncode: PercentTwentyEncoding})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	const workers, loops = 16, 200
+	var wg sync.WaitGroup
+	// redacted view formatted without lock while builder changed
+	redacted := sb.Redacted(RedactOptions{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < loops; i++ {
+			_ = redacted.String()
+		}
+	}()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < loops; i++ {
+				switch i % 8 {
+				case 0:
+					_ = sb.AddQueryParam(AddQueryParamOpt{Key: "w" + strconv.Itoa(w), Val: strconv.Itoa(i)})
+				case 1:
+					sb.SetFragment(SetFragmentOpt{Fragment: "w" + strconv.Itoa(w)})
+				case 2:
+					_ = sb.GetURLResult()
+					_ = sb.GetValueQuery("w0")
+				case 3:
+					sb.DeleteKeyQuery("w" + strconv.Itoa(w))
+				case 4:
+					clone := sb.Clone()
+					clone.SetPath("/w" + strconv.Itoa(w))
+					_ = clone.GetURLResult()
+				case 5:
+					_ = sb.View(func(ub *Builder) error {
+						_ = ub.GetPaths()
+						return nil
+					})
+				case 6:
+					_ = sb.StripTrackingParams()
+					_ = sb.RepairEncoding(RepairOptions{})
+				case 7:
+					_ = sb.GetCampaign()
+					_ = sb.EncodingReport()
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	if got := sb.GetFullPath(); got != "/search" {
+		t.Errorf("fail test SyncBuilder clone changed template got %v want %v", got, "/search")
+	}
+}
+
+func Test_SyncBuilderUpdate(t *testing.T) {
+	sb, err := NewSyncBuilder(Option{URL: "https://www.tokopedia.com/search?counter=0"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// atomic read-modify-write counter
+	const workers, loops = 8, 100
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < loops; i++ {
+				err := sb.Update(func(ub *Builder) error {
+					n, err := strconv.Atoi(ub.GetValueQuery("counter"))
+					if err != nil {
+						return err
+					}
+					ub.setRawQueryValue("counter", strconv.Itoa(n+1))
+					return nil
+				})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	want := strconv.Itoa(workers * loops)
+	if got := sb.GetValueQuery("counter"); got != want {
+		t.Errorf("fail test SyncBuilder Update() got %v want %v", got, want)
+	}
+
+	// failed update not applied
+	errUpdate := errors.New("update failed")
+	err = sb.Update(func(ub *Builder) error {
+		ub.SetPath("/cart")
+		return errUpdate
+	})
+	if !errors.Is(err, errUpdate) {
+		t.Errorf("fail test SyncBuilder Update() got %v want %v", err, errUpdate)
+	}
+	if got := sb.GetFullPath(); got != "/search" {
+		t.Errorf("fail test SyncBuilder Update() applied on error got %v want %v", got, "/search")
+	}
+}
+
+func Test_SyncBuilderUpdateHook(t *testing.T) {
+	events := make([]string, 0)
+	sb, err := NewSyncBuilder(Option{URL: "https://www.tokopedia.com/a", Hooks: []Hook{HookFuncs{
+		Before: func(event ChangeEvent) error {
+			if event.After.Path() == "/admin" {
+				return errorRemoveWarehouse
+			}
+			return nil
+		},
+		After: func(event ChangeEvent) {
+			events = append(events, event.Op+" "+event.After.String())
+		},
+	}}})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// discarded update not notified
+	errUpdate := errors.New("update failed")
+	err = sb.Update(func(ub *Builder) error {
+		ub.SetPath("/b")
+		return errUpdate
+	})
+	if !errors.Is(err, errUpdate) || len(events) > 0 || sb.GetFullPath() != "/a" {
+		t.Errorf("fail test SyncBuilder Update() discarded got %v %v %v", err, events, sb.GetFullPath())
+	}
+
+	// veto inside update
+	err = sb.Update(func(ub *Builder) error {
+		return ub.SetURL("https://www.tokopedia.com/admin")
+	})
+	if !errors.Is(err, ErrorChangeVetoed) || len(events) > 0 {
+		t.Errorf("fail test SyncBuilder Update() vetoed got %v %v", err, events)
+	}
+
+	// applied update notified once applied
+	err = sb.Update(func(ub *Builder) error {
+		ub.SetPath("/b")
+		if len(events) > 0 {
+			return errors.New("notified before update applied")
+		}
+		return nil
+	})
+	want := []string{"SetPath https://www.tokopedia.com/b"}
+	if err != nil || len(events) != 1 || events[0] != want[0] {
+		t.Errorf("fail test SyncBuilder Update() got %v %v want %v", err, events, want)
+	}
+	sb.SetPath("/c")
+	if len(events) != 2 {
+		t.Errorf("fail test SyncBuilder hooks after Update() got %v", events)
+	}
+}
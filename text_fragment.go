@@ -0,0 +1,154 @@
+package uruki
+
+import (
+	"net/url"
+	"strings"
+)
+
+const (
+	fragmentDirectiveDelimiter = ":~:"
+	textDirectivePrefix        = "text="
+)
+
+// TextDirective URL fragment text directive (#:~:text=[prefix-,]start[,end][,-suffix]) for highlighting text
+type TextDirective struct {
+	// Prefix: optional text right before Start
+	Prefix string
+	// Start: text to highlight, or start of highlighted range when End is set. required
+	Start string
+	// End: optional end of highlighted range
+	End string
+	// Suffix: optional text right after highlighted text
+	Suffix string
+}
+
+// String text directive value without "text=", each term escaped including '-', ',' and '&'
+func (td TextDirective) String() string {
+	terms := make([]string, 0, 4)
+	if len(td.Prefix) > 0 {
+		terms = append(terms, escapeTextDirective(td.Prefix)+"-")
+	}
+	terms = append(terms, escapeTextDirective(td.Start))
+	if len(td.End) > 0 {
+		terms = append(terms, escapeTextDirective(td.End))
+	}
+	if len(td.Suffix) > 0 {
+		terms = append(terms, "-"+escapeTextDirective(td.Suffix))
+	}
+	return strings.Join(terms, ",")
+}
+
+// SetTextFragment replace text directives of fragment, element id and other directives of existing fragment kept.
+// without directives text fragment removed
+func (ub *Builder) SetTextFragment(directives ...TextDirective) error {
+	args := make([]string, len(directives))
+	for i, td := range directives {
+		args[i] = td.String()
+	}
+	return ub.mutate("SetTextFragment", args, func() error {
+		elementID, others := splitFragmentDirective(ub.url.EscapedFragment())
+		for _, td := range directives {
+			if len(td.Start) < 1 {
+				return &Error{Op: "SetTextFragment", Component: "fragment", Input: td.String(), Err: ErrorTextDirectiveStart}
+			}
+		}
+		parts := make([]string, 0, len(others)+len(directives))
+		for _, v := range others {
+			if !strings.HasPrefix(v, textDirectivePrefix) {
+				parts = append(parts, v)
+			}
+		}
+		for _, td := range directives {
+			parts = append(parts, textDirectivePrefix+td.String())
+		}
+		rawFragment := elementID
+		if len(parts) > 0 {
+			rawFragment += fragmentDirectiveDelimiter + strings.Join(parts, ampersandStr)
+		}
+		fragment, err := url.PathUnescape(rawFragment)
+		if err != nil {
+			return withOp("SetTextFragment", rawFragment, err)
+		}
+		ub.url.Fragment = fragment
+		ub.url.RawFragment = rawFragment
+		return nil
+	})
+}
+
+// GetTextFragment parse text directives of fragment in order, invalid directive return ErrorTextDirectiveInvalid
+func (ub *Builder) GetTextFragment() ([]TextDirective, error) {
+	_, directives := splitFragmentDirective(ub.url.EscapedFragment())
+	result := make([]TextDirective, 0)
+	for _, v := range directives {
+		if !strings.HasPrefix(v, textDirectivePrefix) {
+			continue
+		}
+		td, err := parseTextDirective(strings.TrimPrefix(v, textDirectivePrefix))
+		if err != nil {
+			return nil, &Error{Op: "GetTextFragment", Component: "fragment", Input: v, Err: err}
+		}
+		result = append(result, td)
+	}
+	return result, nil
+}
+
+// GetFragmentElementID get element id part of fragment, without fragment directive
+func (ub *Builder) GetFragmentElementID() string {
+	elementID, _ := splitFragmentDirective(ub.url.EscapedFragment())
+	id, err := url.PathUnescape(elementID)
+	if err != nil {
+		return elementID
+	}
+	return id
+}
+
+// splitFragmentDirective split raw fragment into element id and directives
+func splitFragmentDirective(rawFragment string) (string, []string) {
+	elementID, directive, found := strings.Cut(rawFragment, fragmentDirectiveDelimiter)
+	if !found || len(directive) < 1 {
+		return elementID, nil
+	}
+	return elementID, strings.Split(directive, ampersandStr)
+}
+
+// parseTextDirective parse raw text directive value without "text="
+func parseTextDirective(raw string) (TextDirective, error) {
+	terms := strings.Split(raw, ",")
+	if len(terms) > 4 {
+		return TextDirective{}, ErrorTextDirectiveInvalid
+	}
+	td := TextDirective{}
+	if len(terms) > 1 && strings.HasSuffix(terms[0], "-") {
+		td.Prefix = strings.TrimSuffix(terms[0], "-")
+		terms = terms[1:]
+	}
+	if len(terms) > 1 && strings.HasPrefix(terms[len(terms)-1], "-") {
+		td.Suffix = strings.TrimPrefix(terms[len(terms)-1], "-")
+		terms = terms[:len(terms)-1]
+	}
+	if len(terms) < 1 || len(terms) > 2 {
+		return TextDirective{}, ErrorTextDirectiveInvalid
+	}
+	td.Start = terms[0]
+	if len(terms) == 2 {
+		td.End = terms[1]
+	}
+	for _, term := range []*string{&td.Prefix, &td.Start, &td.End, &td.Suffix} {
+		decoded, err := url.PathUnescape(*term)
+		if err != nil {
+			return TextDirective{}, ErrorTextDirectiveInvalid
+		}
+		*term = decoded
+	}
+	if len(td.Start) < 1 {
+		return TextDirective{}, ErrorTextDirectiveInvalid
+	}
+	return td, nil
+}
+
+// escapeTextDirective percent encode term of text directive, only unreserved characters except '-' kept
+func escapeTextDirective(term string) string {
+	return percentEncode(term, func(c byte) bool {
+		return c != '-' && isUnreserved(c)
+	}, "", false)
+}
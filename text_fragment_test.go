@@ -0,0 +1,138 @@
+package uruki
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_SetTextFragment(t *testing.T) {
+	type args struct {
+		name       string
+		url        string
+		directives []TextDirective
+		wantURL    string
+		wantErr    error
+	}
+
+	testCases := []args{
+		{
+			name:       "single start",
+			url:        "https://www.tokopedia.com/help/article/refund",
+			directives: []TextDirective{{Start: "refund policy"}},
+			wantURL:    "https://www.tokopedia.com/help/article/refund#:~:text=refund%20policy",
+		},
+		{
+			name: "escape dash comma ampersand with prefix, end and suffix",
+			url:  "https://www.tokopedia.com/help/article/refund",
+			directives: []TextDirective{
+				{Prefix: "step-1", Start: "P&G, Unilever", End: "done", Suffix: "-end"},
+			},
+			wantURL: "https://www.tokopedia.com/help/article/refund#:~:text=step%2D1-,P%26G%2C%20Unilever,done,-%2Dend",
+		},
+		{
+			name:       "multiple directives keep element id and replace old text",
+			url:        "https://www.tokopedia.com/help/article/refund#faq:~:text=old&foo=bar",
+			directives: []TextDirective{{Start: "first"}, {Start: "second"}},
+			wantURL:    "https://www.tokopedia.com/help/article/refund#faq:~:foo=bar&text=first&text=second",
+		},
+		{
+			name:    "remove text directive",
+			url:     "https://www.tokopedia.com/help/article/refund#faq:~:text=old",
+			wantURL: "https://www.tokopedia.com/help/article/refund#faq",
+		},
+		{
+			name:       "empty start",
+			url:        "https://www.tokopedia.com/help#faq",
+			directives: []TextDirective{{Prefix: "a"}},
+			wantURL:    "https://www.tokopedia.com/help#faq",
+			wantErr:    ErrorTextDirectiveStart,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ub, err := NewBuilder(Option{URL: tt.url})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			err = ub.SetTextFragment(tt.directives...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("fail error test SetTextFragment() got %v want %v", err, tt.wantErr)
+			}
+			if got := ub.GetURLResult(); got != tt.wantURL {
+				t.Errorf("fail value test SetTextFragment() got %v want %v", got, tt.wantURL)
+			}
+			if err != nil {
+				return
+			}
+			got, err := ub.GetTextFragment()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if len(got) != len(tt.directives) || (len(got) > 0 && !reflect.DeepEqual(got, tt.directives)) {
+				t.Errorf("fail round trip test GetTextFragment() got %v want %v", got, tt.directives)
+			}
+		})
+	}
+}
+
+func Test_GetTextFragment(t *testing.T) {
+	type args struct {
+		name          string
+		url           string
+		want          []TextDirective
+		wantElementID string
+		wantErr       error
+	}
+
+	testCases := []args{
+		{
+			name:          "range with prefix and suffix",
+			url:           "https://example.com/page#intro:~:text=an%20example-,text%20fragment,end%20here,-and%20more",
+			want:          []TextDirective{{Prefix: "an example", Start: "text fragment", End: "end here", Suffix: "and more"}},
+			wantElementID: "intro",
+		},
+		{
+			name: "start with suffix only",
+			url:  "https://example.com/page#:~:text=foo,-bar&text=baz",
+			want: []TextDirective{{Start: "foo", Suffix: "bar"}, {Start: "baz"}},
+		},
+		{
+			name:          "no directive",
+			url:           "https://example.com/page#intro",
+			want:          []TextDirective{},
+			wantElementID: "intro",
+		},
+		{
+			name:    "too many terms",
+			url:     "https://example.com/page#:~:text=a-,b,c,d,-e",
+			wantErr: ErrorTextDirectiveInvalid,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ub, err := NewBuilder(Option{URL: tt.url})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			got, err := ub.GetTextFragment()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("fail error test GetTextFragment() got %v want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fail value test GetTextFragment() got %v want %v", got, tt.want)
+			}
+			if id := ub.GetFragmentElementID(); id != tt.wantElementID {
+				t.Errorf("fail test GetFragmentElementID() got %v want %v", id, tt.wantElementID)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package uruki
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// StripProfile rule set of query parameter to strip by StripTrackingParams. key, prefix and host are case insensitive,
+// patterns matched against lower cased key
+type StripProfile struct {
+	// Name: profile name, reported on StrippedParam
+	Name string
+	// Keys: exact keys to strip
+	Keys []string
+	// Prefixes: strip key with any of prefixes
+	Prefixes []string
+	// Patterns: strip key matching any of patterns
+	Patterns []*regexp.Regexp
+	// Hosts: only apply profile on these hosts, use "*.tokopedia.com" for subdomains. default apply on any host
+	Hosts []string
+}
+
+// StrippedParam query parameter removed by StripTrackingParams, with decoded key and value
+type StrippedParam struct {
+	Profile string
+	Key     string
+	Value   string
+}
+
+// built in profiles of StripTrackingParams
+var (
+	// StripProfileUTM google analytics campaign parameters
+	StripProfileUTM = StripProfile{
+		Name:     "utm",
+		Prefixes: []string{"utm_"},
+	}
+	// StripProfileAdClickID ad platform click identifiers
+	StripProfileAdClickID = StripProfile{
+		Name: "ad_click_id",
+		Keys: []string{"gclid", "gclsrc", "dclid", "gbraid", "wbraid", "fbclid", "msclkid", "yclid", "ttclid", "twclid", "li_fat_id", "epik", "irclickid"},
+	}
+	// StripProfileSocial common social and email marketing trackers
+	StripProfileSocial = StripProfile{
+		Name:     "social",
+		Keys:     []string{"igshid", "igsh", "mc_cid", "mc_eid", "_hsenc", "_hsmi", "mkt_tok", "vero_id", "oly_anon_id", "oly_enc_id", "ref_src", "ref_url"},
+		Prefixes: []string{"__hs"},
+	}
+)
+
+// StripTrackingParams remove tracking query parameters matched by profiles, default using all built in profiles.
+// other query parameters keep their original encoding. return removed parameters in order of appearance,
+// empty when the change vetoed by hook
+func (ub *Builder) StripTrackingParams(profiles ...StripProfile) []StrippedParam {
+	if len(profiles) < 1 {
+		profiles = []StripProfile{StripProfileUTM, StripProfileAdClickID, StripProfileSocial}
+	}
+	names := make([]string, len(profiles))
+	for i, p := range profiles {
+		names[i] = p.Name
+	}
+	stripped := make([]StrippedParam, 0)
+	err := ub.mutate("StripTrackingParams", names, func() error {
+		applied := make([]StripProfile, 0, len(profiles))
+		for _, p := range profiles {
+			if len(p.Hosts) < 1 || hostAllowed(ub.url.Hostname(), p.Hosts) {
+				applied = append(applied, p)
+			}
+		}
+		if len(ub.url.RawQuery) < 1 || len(applied) < 1 {
+			return nil
+		}
+		keyVal := strings.Split(ub.url.RawQuery, ampersandStr)
+		buildRawResult := make([]string, 0, len(keyVal))
+		for _, queryParam := range keyVal {
+			q, v, _ := strings.Cut(queryParam, "=")
+			key, err := url.QueryUnescape(q)
+			if err != nil {
+				key = q
+			}
+			profile, ok := matchStripProfile(strings.ToLower(key), applied)
+			if !ok {
+				buildRawResult = append(buildRawResult, queryParam)
+				continue
+			}
+			value, err := url.QueryUnescape(v)
+			if err != nil {
+				value = v
+			}
+			stripped = append(stripped, StrippedParam{Profile: profile, Key: key, Value: value})
+		}
+		ub.url.RawQuery = strings.Join(buildRawResult, ampersandStr)
+		return nil
+	})
+	if err != nil {
+		return make([]StrippedParam, 0)
+	}
+	return stripped
+}
+
+// matchStripProfile find first profile matched the lower cased key
+func matchStripProfile(key string, profiles []StripProfile) (string, bool) {
+	if len(key) < 1 {
+		return "", false
+	}
+	for _, p := range profiles {
+		for _, v := range p.Keys {
+			if key == strings.ToLower(v) {
+				return p.Name, true
+			}
+		}
+		for _, v := range p.Prefixes {
+			if strings.HasPrefix(key, strings.ToLower(v)) {
+				return p.Name, true
+			}
+		}
+		for _, re := range p.Patterns {
+			if re.MatchString(key) {
+				return p.Name, true
+			}
+		}
+	}
+	return "", false
+}
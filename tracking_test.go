@@ -0,0 +1,97 @@
+package uruki
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func Test_StripTrackingParams(t *testing.T) {
+	type args struct {
+		name         string
+		url          string
+		profiles     []StripProfile
+		wantURL      string
+		wantStripped []StrippedParam
+	}
+
+	testCases := []args{
+		{
+			name:    "default built in profiles",
+			url:     "https://www.tokopedia.com/search?q=beras%20putih&utm_source=fb&UTM_Medium=cpc&fbclid=IwAR0&gclid=Cj0&igshid=abc&st=product",
+			wantURL: "https://www.tokopedia.com/search?q=beras%20putih&st=product",
+			wantStripped: []StrippedParam{
+				{Profile: "utm", Key: "utm_source", Value: "fb"},
+				{Profile: "utm", Key: "UTM_Medium", Value: "cpc"},
+				{Profile: "ad_click_id", Key: "fbclid", Value: "IwAR0"},
+				{Profile: "ad_click_id", Key: "gclid", Value: "Cj0"},
+				{Profile: "social", Key: "igshid", Value: "abc"},
+			},
+		},
+		{
+			name:         "nothing to strip",
+			url:          "https://www.tokopedia.com/search?q=beras",
+			wantURL:      "https://www.tokopedia.com/search?q=beras",
+			wantStripped: []StrippedParam{},
+		},
+		{
+			name: "user defined profile with key, prefix and pattern",
+			url:  "https://www.tokopedia.com/search?srp_component_id=02.01.00.00&srp_page_id=&q=macbook&trk_ab=1&ref=home",
+			profiles: []StripProfile{
+				{
+					Name:     "srp",
+					Keys:     []string{"ref"},
+					Prefixes: []string{"srp_"},
+					Patterns: []*regexp.Regexp{regexp.MustCompile(`^trk_[a-z]+$`)},
+				},
+			},
+			wantURL: "https://www.tokopedia.com/search?q=macbook",
+			wantStripped: []StrippedParam{
+				{Profile: "srp", Key: "srp_component_id", Value: "02.01.00.00"},
+				{Profile: "srp", Key: "srp_page_id", Value: ""},
+				{Profile: "srp", Key: "trk_ab", Value: "1"},
+				{Profile: "srp", Key: "ref", Value: "home"},
+			},
+		},
+		{
+			name: "host scoped profile not applied on other host",
+			url:  "https://www.bukalapak.com/search?ref=home&utm_source=x",
+			profiles: []StripProfile{
+				{Name: "toped_ref", Keys: []string{"ref"}, Hosts: []string{"*.tokopedia.com"}},
+				StripProfileUTM,
+			},
+			wantURL: "https://www.bukalapak.com/search?ref=home",
+			wantStripped: []StrippedParam{
+				{Profile: "utm", Key: "utm_source", Value: "x"},
+			},
+		},
+		{
+			name: "host scoped profile applied",
+			url:  "https://www.tokopedia.com/search?ref=home&q=x",
+			profiles: []StripProfile{
+				{Name: "toped_ref", Keys: []string{"ref"}, Hosts: []string{"*.tokopedia.com"}},
+			},
+			wantURL: "https://www.tokopedia.com/search?q=x",
+			wantStripped: []StrippedParam{
+				{Profile: "toped_ref", Key: "ref", Value: "home"},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ub, err := NewBuilder(Option{URL: tt.url})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			got := ub.StripTrackingParams(tt.profiles...)
+			if !reflect.DeepEqual(got, tt.wantStripped) {
+				t.Errorf("fail test StripTrackingParams() got %v want %v", got, tt.wantStripped)
+			}
+			if ub.GetURLResult() != tt.wantURL {
+				t.Errorf("fail value test StripTrackingParams() got %v want %v", ub.GetURLResult(), tt.wantURL)
+			}
+		})
+	}
+}
@@ -11,6 +11,16 @@ const (
 	ampersandStr = "&"
 )
 
+// ParseMode parser used to parse url of Builder
+type ParseMode int
+
+// constants of ParseMode
+const (
+	NetURL        ParseMode = iota // Parse with net/url as is
+	RFC3986Strict                  // Reject character not allowed by RFC 3986 then parse with net/url
+	WHATWG                         // Parse with WHATWG URL Standard the same way browsers parse url
+)
+
 var (
 	// ErrorInvalidSchemeURI error invalid scheme url not in restricted schemes
 	ErrorInvalidSchemeURI = errors.New("invalid scheme url not in restricted schemes")
@@ -18,4 +28,70 @@ var (
 	ErrorKeyEmpty = errors.New("key query parameter cannot be empty")
 	// ErrorKeyContainSpace key query parameter cannot contains space
 	ErrorKeyContainSpace = errors.New("key query parameter cannot contains space")
+	// ErrorKeyNotFound key query parameter not found
+	ErrorKeyNotFound = errors.New("key query parameter not found")
+	// ErrorChildNil child builder to write back cannot be nil
+	ErrorChildNil = errors.New("child builder cannot be nil")
+	// ErrorRedirectBase base url for redirect must be absolute url with host
+	ErrorRedirectBase = errors.New("base url for redirect must be absolute url with host")
+	// ErrorRedirectEmpty redirect target cannot be empty
+	ErrorRedirectEmpty = errors.New("redirect target cannot be empty")
+	// ErrorRedirectScheme redirect target scheme is not allowed
+	ErrorRedirectScheme = errors.New("redirect target scheme is not allowed")
+	// ErrorRedirectHost redirect target host is not same origin nor allowed host
+	ErrorRedirectHost = errors.New("redirect target host is not same origin nor allowed host")
+	// ErrorRedirectUserinfo redirect target cannot contains userinfo
+	ErrorRedirectUserinfo = errors.New("redirect target cannot contains userinfo")
+	// ErrorCampaignRequired campaign source, medium and name are required
+	ErrorCampaignRequired = errors.New("campaign field is required")
+	// ErrorSignKeyEmpty key for signing url cannot be empty
+	ErrorSignKeyEmpty = errors.New("key for signing url cannot be empty")
+	// ErrorSignAlgorithm signing algorithm is not supported
+	ErrorSignAlgorithm = errors.New("signing algorithm is not supported")
+	// ErrorSignatureMissing signature parameter is missing from url
+	ErrorSignatureMissing = errors.New("signature parameter is missing from url")
+	// ErrorSignatureKeyUnknown key id of signed url is unknown
+	ErrorSignatureKeyUnknown = errors.New("key id of signed url is unknown")
+	// ErrorSignatureInvalid signature not match, url has been tampered
+	ErrorSignatureInvalid = errors.New("signature not match, url has been tampered")
+	// ErrorSignatureExpired signed url already expired
+	ErrorSignatureExpired = errors.New("signed url already expired")
+	// ErrorPresignCredential access key id, secret access key and region are required for presign
+	ErrorPresignCredential = errors.New("access key id, secret access key and region are required for presign")
+	// ErrorPresignHost url to presign must have host
+	ErrorPresignHost = errors.New("url to presign must have host")
+	// ErrorPresignExpires presign expires must between 1 second and 7 days
+	ErrorPresignExpires = errors.New("presign expires must between 1 second and 7 days")
+	// ErrorTextDirectiveStart start of text directive cannot be empty
+	ErrorTextDirectiveStart = errors.New("start of text directive cannot be empty")
+	// ErrorTextDirectiveInvalid text directive of fragment is invalid
+	ErrorTextDirectiveInvalid = errors.New("text directive of fragment is invalid")
+	// ErrorWHATWGFailure url cannot be parsed by WHATWG URL Standard
+	ErrorWHATWGFailure = errors.New("url cannot be parsed by WHATWG URL Standard")
+	// ErrorRFC3986Invalid url contains character not allowed by RFC 3986
+	ErrorRFC3986Invalid = errors.New("url contains character not allowed by RFC 3986")
+	// ErrorMalformedQuery query parameter contains malformed percent escape
+	ErrorMalformedQuery = errors.New("query parameter contains malformed percent escape")
+	// ErrorSchemeInvalid scheme must start with letter followed by letters, digits, '+', '-' or '.'
+	ErrorSchemeInvalid = errors.New("scheme must start with letter followed by letters, digits, '+', '-' or '.'")
+	// ErrorHostInvalid host must be valid RFC 3986 host with optional port
+	ErrorHostInvalid = errors.New("host must be valid RFC 3986 host with optional port")
+	// ErrorOptionInvalid builder option value is invalid
+	ErrorOptionInvalid = errors.New("builder option value is invalid")
+	// ErrorOptionConflict builder options are conflicting
+	ErrorOptionConflict = errors.New("builder options are conflicting")
+	// ErrorHistoryDisabled builder created without history, see Option HistorySize
+	ErrorHistoryDisabled = errors.New("builder history is disabled")
+	// ErrorNothingToUndo no applied mutation in history
+	ErrorNothingToUndo = errors.New("nothing to undo")
+	// ErrorNothingToRedo no undone mutation in history
+	ErrorNothingToRedo = errors.New("nothing to redo")
+	// ErrorSnapshotNotFound snapshot id not taken by the builder
+	ErrorSnapshotNotFound = errors.New("snapshot not found")
+	// ErrorChangeVetoed change of url vetoed by hook
+	ErrorChangeVetoed = errors.New("change vetoed by hook")
+	// ErrorBinaryInvalid binary encoding of builder is empty or has unknown version
+	ErrorBinaryInvalid = errors.New("invalid binary encoding of builder")
+	// ErrorScanType sql value type can not be scanned into builder, only string, []byte and NULL supported
+	ErrorScanType = errors.New("unsupported sql value type for builder")
 )
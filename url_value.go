@@ -0,0 +1,169 @@
+package uruki
+
+import (
+	"net/url"
+	"strings"
+)
+
+// URL immutable url value, safe to share between goroutines. every With method return new value
+// and never change the receiver
+type URL struct {
+	uri url.URL
+}
+
+// ParseURL parse raw url into immutable URL with net/url
+func ParseURL(raw string) (URL, error) {
+	uri, err := url.Parse(raw)
+	if err != nil {
+		return URL{}, err
+	}
+	return URL{uri: *uri}, nil
+}
+
+// URLFrom immutable URL from net/url URL, userinfo copied
+func URLFrom(uri url.URL) URL {
+	return URL{uri: *copyURL(&uri)}
+}
+
+// URL immutable snapshot of builder url, later change of builder not reflected
+func (ub *Builder) URL() URL {
+	return URLFrom(*ub.url)
+}
+
+// Builder new builder from url parsed with builder options merged like NewBuilder, URL of option replaced by url
+func (u URL) Builder(options ...Option) (*Builder, error) {
+	opt, err := mergeOptions(options)
+	if err != nil {
+		return nil, &Error{Op: "Builder", Component: "option", Err: err}
+	}
+	opt.URL = u.String()
+	return NewBuilder(opt)
+}
+
+// NetURL net/url URL copy of url, userinfo copied
+func (u URL) NetURL() url.URL {
+	return *copyURL(&u.uri)
+}
+
+// String url result with escaped option
+func (u URL) String() string {
+	return u.uri.String()
+}
+
+// Scheme scheme of url
+func (u URL) Scheme() string {
+	return u.uri.Scheme
+}
+
+// User userinfo of url, nil if not set. url.Userinfo is immutable
+func (u URL) User() *url.Userinfo {
+	return u.uri.User
+}
+
+// Host host of url with port if any
+func (u URL) Host() string {
+	return u.uri.Host
+}
+
+// Hostname host of url without port
+func (u URL) Hostname() string {
+	return u.uri.Hostname()
+}
+
+// Port port of url, empty if not set
+func (u URL) Port() string {
+	return u.uri.Port()
+}
+
+// Path decoded path of url
+func (u URL) Path() string {
+	return u.uri.Path
+}
+
+// EscapedPath escaped path of url
+func (u URL) EscapedPath() string {
+	return u.uri.EscapedPath()
+}
+
+// RawQuery encoded query of url without '?'
+func (u URL) RawQuery() string {
+	return u.uri.RawQuery
+}
+
+// Query decoded first value of query key
+func (u URL) Query(key string) string {
+	val, _ := lookupQueryPair(parseRawQueryPairs(u.uri.RawQuery), key)
+	return val
+}
+
+// Fragment decoded fragment of url without '#'
+func (u URL) Fragment() string {
+	return u.uri.Fragment
+}
+
+// WithScheme new url with scheme
+func (u URL) WithScheme(scheme string) URL {
+	u.uri.Scheme = strings.ToLower(scheme)
+	return u
+}
+
+// WithUser new url with username and password, empty password set username only
+func (u URL) WithUser(username, password string) URL {
+	if len(password) > 0 {
+		u.uri.User = url.UserPassword(username, password)
+	} else {
+		u.uri.User = url.User(username)
+	}
+	return u
+}
+
+// WithoutUser new url without userinfo
+func (u URL) WithoutUser() URL {
+	u.uri.User = nil
+	return u
+}
+
+// WithHost new url with host, port can be included
+func (u URL) WithHost(host string) URL {
+	u.uri.Host = host
+	return u
+}
+
+// WithPath new url with decoded path
+func (u URL) WithPath(path string) URL {
+	u.uri.Path = path
+	u.uri.RawPath = ""
+	return u
+}
+
+// WithRawQuery new url with encoded query without '?'
+func (u URL) WithRawQuery(rawQuery string) URL {
+	u.uri.RawQuery = rawQuery
+	return u
+}
+
+// WithQuery new url with value of first existing key replaced, otherwise appended. value query escaped
+func (u URL) WithQuery(key, val string) URL {
+	ub := &Builder{url: &u.uri}
+	ub.setRawQueryValue(key, url.QueryEscape(val))
+	return u
+}
+
+// WithoutQuery new url without all parameter of key, other parameters kept as is
+func (u URL) WithoutQuery(key string) URL {
+	ub := &Builder{url: &u.uri}
+	ub.deleteRawQueryKeys(key)
+	return u
+}
+
+// WithFragment new url with decoded fragment
+func (u URL) WithFragment(fragment string) URL {
+	u.uri.Fragment = fragment
+	u.uri.RawFragment = ""
+	return u
+}
+
+// WithoutFragment new url without fragment
+func (u URL) WithoutFragment() URL {
+	return u.WithFragment("")
+}
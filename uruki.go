@@ -1,13 +1,14 @@
 package uruki
 
 import (
-	"errors"
 	"net/url"
 )
 
 var (
-	// ErrInvalidURL invalid url
-	ErrInvalidURL = errors.New("invalid scheme url, it should https or http prefix")
+	// ErrInvalidURL invalid scheme url.
+	//
+	// Deprecated: use ErrorInvalidSchemeURI, ErrInvalidURL is the same error value
+	ErrInvalidURL = ErrorInvalidSchemeURI
 )
 
 // Builder base struct uruki
@@ -16,6 +17,16 @@ type Builder struct {
 	defaultSpaceEncode   string
 	restrictedScheme     map[string]bool
 	useEscapeAutomateURL bool
+	encodeSet            EncodeSet
+	parseMode            ParseMode
+	strict               bool
+	malformedPolicy      MalformedPolicy
+	history              *history
+	snapshots            map[SnapshotID]URL
+	lastSnapshot         SnapshotID
+	mutating             bool
+	hooks                []Hook
+	jsonFormat           JSONFormat
 }
 
 // Option options to create new Builder
@@ -28,23 +39,72 @@ type Option struct {
 	DefaultSpaceEncode string
 	// UseEscapeAutomateURL to automate query escape on existing url query parameter while initiating builder / SetURL(uri string)
 	UseEscapeAutomateURL bool
+	// EncodeSet: percent-encode set used by setters to escape each component, default EncodeLegacy. see EncodeSet const for more the details
+	EncodeSet EncodeSet
+	// ParseMode: parser used to parse url, default NetURL. see ParseMode const for more the details
+	ParseMode ParseMode
+	// Strict: reject url not valid by RFC 3986 while initiating builder / SetURL(uri string), see Validate
+	Strict bool
+	// MalformedPolicy: how UseEscapeAutomateURL handle malformed percent escape of existing query, default MalformedKeepRaw.
+	// see MalformedPolicy const for more the details
+	MalformedPolicy MalformedPolicy
+	// HistorySize: max mutations kept in history for Undo / Redo, default 0 history disabled
+	HistorySize int
+	// Hooks: hooks notified on every change of url in order, BeforeChange of hook can veto the change. see Hook
+	Hooks []Hook
+	// JSONFormat: format of builder encoded as JSON, default JSONString. see JSONFormat const for more the details
+	JSONFormat JSONFormat
 }
 
-// NewBuilder create uruki (URi qUicK buIlder) parser & wrapper of net/url
+// NewBuilder create uruki (URi qUicK buIlder) parser & wrapper of net/url. multiple options merged, the same
+// setting repeated with different value return ErrorOptionConflict. use New to compose functional options
 func NewBuilder(options ...Option) (*Builder, error) {
-	ub := &Builder{url: &url.URL{}}
+	ub := &Builder{url: &url.URL{}, encodeSet: EncodeLegacy}
 	if len(options) > 0 {
-		opt := options[0]
+		opt, err := mergeOptions(options)
+		if err != nil {
+			return nil, &Error{Op: "NewBuilder", Component: "option", Err: err}
+		}
 		ub.defaultSpaceEncode = opt.DefaultSpaceEncode
 		ub.useEscapeAutomateURL = opt.UseEscapeAutomateURL
+		ub.parseMode = opt.ParseMode
+		ub.strict = opt.Strict
+		ub.malformedPolicy = opt.MalformedPolicy
+		ub.history = newHistory(opt.HistorySize)
+		ub.hooks = append([]Hook{}, opt.Hooks...)
+		ub.jsonFormat = opt.JSONFormat
+		if opt.EncodeSet != EncodeDefault {
+			ub.encodeSet = opt.EncodeSet
+		}
 		ub.setRestrictedScheme(opt.RestrictScheme)
-		err := ub.setURL(opt.URL)
-		if err != nil {
-			return nil, err
+		if err := ub.setURL(opt.URL); err != nil {
+			return nil, withOp("NewBuilder", opt.URL, err)
 		}
 		if ub.useEscapeAutomateURL {
-			ub.queryEscapeAutomate()
+			if err := ub.queryEscapeAutomate(); err != nil {
+				return nil, withOp("NewBuilder", opt.URL, err)
+			}
 		}
 	}
 	return ub, nil
 }
+
+// Clone deep copy of builder with the same options, changes of clone not affect the builder
+func (ub *Builder) Clone() *Builder {
+	clone := ub.derive(copyURL(ub.url))
+	clone.hooks = ub.hooks
+	restrictedScheme := make(map[string]bool, len(ub.restrictedScheme))
+	for k, v := range ub.restrictedScheme {
+		restrictedScheme[k] = v
+	}
+	clone.restrictedScheme = restrictedScheme
+	clone.history = ub.history.copy()
+	clone.lastSnapshot = ub.lastSnapshot
+	if ub.snapshots != nil {
+		clone.snapshots = make(map[SnapshotID]URL, len(ub.snapshots))
+		for k, v := range ub.snapshots {
+			clone.snapshots[k] = v
+		}
+	}
+	return clone
+}
@@ -0,0 +1,172 @@
+package uruki
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError position and reason of character not allowed by RFC 3986
+type ValidationError struct {
+	// Component: url component of offending character, one of scheme, userinfo, host, port, path, query, fragment
+	Component string
+	// Offset: byte offset of offending character on raw url
+	Offset int
+	// Char: offending character
+	Char string
+	// Fix: suggested fix
+	Fix string
+}
+
+// Error message of validation error
+func (ve ValidationError) Error() string {
+	return fmt.Sprintf("%s: invalid %s character %q at offset %d, %s", ErrorRFC3986Invalid, ve.Component, ve.Char, ve.Offset, ve.Fix)
+}
+
+// Unwrap validation error is ErrorRFC3986Invalid
+func (ve ValidationError) Unwrap() error {
+	return ErrorRFC3986Invalid
+}
+
+// characters allowed by RFC 3986 besides unreserved and percent escape
+const (
+	rfc3986SubDelims = "!$&'()*+,;="
+	rfc3986PChar     = rfc3986SubDelims + ":@"
+)
+
+// Validate validate raw url (absolute or relative reference) against RFC 3986 grammar,
+// return all offending characters in order. empty result mean url is valid
+func Validate(raw string) []ValidationError {
+	errs := make([]ValidationError, 0)
+	rest, offset := raw, 0
+
+	// scheme, only when ':' come before any '/', '?' or '#'
+	if i := strings.IndexAny(rest, ":/?#"); i > 0 && rest[i] == ':' {
+		for j := 0; j < i; j++ {
+			c := rest[j]
+			if isASCIIAlpha(rune(c)) || (j > 0 && (('0' <= c && c <= '9') || c == '+' || c == '-' || c == '.')) {
+				continue
+			}
+			ve := newValidationError("scheme", rest, j, offset,
+				"scheme must start with letter followed by letters, digits, '+', '-' or '.', prefix relative path containing ':' with \"./\"")
+			errs = append(errs, ve)
+			j += len(ve.Char) - 1
+		}
+		rest, offset = rest[i+1:], offset+i+1
+	}
+
+	// authority
+	if strings.HasPrefix(rest, "//") {
+		rest, offset = rest[2:], offset+2
+		end := strings.IndexAny(rest, "/?#")
+		if end < 0 {
+			end = len(rest)
+		}
+		errs = append(errs, validateAuthority(rest[:end], offset)...)
+		rest, offset = rest[end:], offset+end
+	}
+
+	// path, query and fragment
+	components := []struct {
+		name    string
+		prefix  byte
+		end     string
+		allowed string
+	}{
+		{"path", 0, "?#", rfc3986PChar + "/"},
+		{"query", '?', "#", rfc3986PChar + "/?"},
+		{"fragment", '#', "", rfc3986PChar + "/?"},
+	}
+	for _, component := range components {
+		if component.prefix != 0 {
+			if len(rest) < 1 || rest[0] != component.prefix {
+				continue
+			}
+			rest, offset = rest[1:], offset+1
+		}
+		end := len(rest)
+		if len(component.end) > 0 {
+			if i := strings.IndexAny(rest, component.end); i >= 0 {
+				end = i
+			}
+		}
+		errs = append(errs, validateChars(component.name, rest[:end], offset, component.allowed)...)
+		rest, offset = rest[end:], offset+end
+	}
+	return errs
+}
+
+// validateAuthority validate userinfo, host and port of authority
+func validateAuthority(authority string, offset int) []ValidationError {
+	errs := make([]ValidationError, 0)
+	if i := strings.LastIndexByte(authority, '@'); i >= 0 {
+		errs = append(errs, validateChars("userinfo", authority[:i], offset, rfc3986SubDelims+":")...)
+		authority, offset = authority[i+1:], offset+i+1
+	}
+	host, port := authority, ""
+	if strings.HasPrefix(authority, "[") {
+		end := strings.IndexByte(authority, ']')
+		if end < 0 {
+			return append(errs, newValidationError("host", authority, 0, offset, "close IP literal with ']'"))
+		}
+		host, port = authority[:end+1], authority[end+1:]
+		errs = append(errs, validateChars("host", host[1:end], offset+1, rfc3986SubDelims+":")...)
+		if len(port) > 0 && port[0] != ':' {
+			return append(errs, newValidationError("port", authority, end+1, offset, "separate port from host with ':'"))
+		}
+	} else {
+		if i := strings.LastIndexByte(authority, ':'); i >= 0 {
+			host, port = authority[:i], authority[i:]
+		}
+		errs = append(errs, validateChars("host", host, offset, rfc3986SubDelims)...)
+	}
+	for i := 1; i < len(port); i++ {
+		if port[i] < '0' || port[i] > '9' {
+			ve := newValidationError("port", port, i, offset+len(host), "port must contain digits only")
+			errs = append(errs, ve)
+			i += len(ve.Char) - 1
+		}
+	}
+	return errs
+}
+
+// validateChars validate each character of component is unreserved, valid percent escape or one of allowed
+func validateChars(component, s string, offset int, allowed string) []ValidationError {
+	errs := make([]ValidationError, 0)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '%':
+			if i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]) {
+				i += 2
+				continue
+			}
+			errs = append(errs, ValidationError{
+				Component: component,
+				Offset:    offset + i,
+				Char:      "%",
+				Fix:       "escape '%' as %25 or complete the escape with two hex digits",
+			})
+		case isUnreserved(c) || strings.IndexByte(allowed, c) >= 0:
+		default:
+			ve := newValidationError(component, s, i, offset, "")
+			errs = append(errs, ve)
+			i += len(ve.Char) - 1
+		}
+	}
+	return errs
+}
+
+// newValidationError validation error of character at i of s which start at offset of raw url,
+// percent encoding suggested when fix is empty
+func newValidationError(component, s string, i, offset int, fix string) ValidationError {
+	char := s[i : i+1]
+	if s[i] >= utf8.RuneSelf {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		char = s[i : i+size]
+	}
+	if len(fix) < 1 {
+		fix = "percent encode as " + percentEncode(char, func(c byte) bool { return false }, "", false)
+	}
+	return ValidationError{Component: component, Offset: offset + i, Char: char, Fix: fix}
+}
@@ -0,0 +1,667 @@
+package uruki
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// WHATWGURL url record parsed by WHATWG URL Standard (https://url.spec.whatwg.org), the same way browsers parse url
+type WHATWGURL struct {
+	scheme     string
+	username   string
+	password   string
+	host       *string
+	port       int
+	path       []string
+	opaquePath *string
+	query      *string
+	fragment   *string
+}
+
+// whatwg parser states
+type whatwgState int
+
+const (
+	stateSchemeStart whatwgState = iota
+	stateScheme
+	stateNoScheme
+	stateSpecialRelativeOrAuthority
+	statePathOrAuthority
+	stateRelative
+	stateRelativeSlash
+	stateSpecialAuthoritySlashes
+	stateSpecialAuthorityIgnoreSlashes
+	stateAuthority
+	stateHost
+	statePort
+	stateFile
+	stateFileSlash
+	stateFileHost
+	statePathStart
+	statePath
+	stateOpaquePath
+	stateQuery
+	stateFragment
+)
+
+const eof = rune(-1)
+
+// whatwgFailure error of WHATWG parser with validation error type as reason
+func whatwgFailure(reason string) error {
+	return fmt.Errorf("%w: %s", ErrorWHATWGFailure, reason)
+}
+
+// ParseWHATWG parse absolute url by WHATWG URL Standard basic url parser
+func ParseWHATWG(input string) (*WHATWGURL, error) {
+	return whatwgParse(input, nil)
+}
+
+// Parse parse url reference relative to u by WHATWG URL Standard basic url parser
+func (u *WHATWGURL) Parse(ref string) (*WHATWGURL, error) {
+	return whatwgParse(ref, u)
+}
+
+// whatwgParse basic url parser without state override
+func whatwgParse(raw string, base *WHATWGURL) (*WHATWGURL, error) {
+	raw = strings.TrimFunc(raw, func(c rune) bool {
+		return c <= 0x20
+	})
+	raw = strings.Map(func(c rune) rune {
+		if c == '\t' || c == '\n' || c == '\r' {
+			return -1
+		}
+		return c
+	}, raw)
+	input := []rune(raw)
+
+	u := &WHATWGURL{port: -1}
+	state := stateSchemeStart
+	buffer := make([]rune, 0)
+	atSignSeen, insideBrackets, passwordTokenSeen := false, false, false
+	remaining := func(pointer int) []rune {
+		if pointer+1 < len(input) {
+			return input[pointer+1:]
+		}
+		return nil
+	}
+
+	for pointer := 0; pointer <= len(input); pointer++ {
+		c := eof
+		if pointer < len(input) {
+			c = input[pointer]
+		}
+		switch state {
+		case stateSchemeStart:
+			if isASCIIAlpha(c) {
+				buffer = append(buffer, toASCIILower(c))
+				state = stateScheme
+			} else {
+				state = stateNoScheme
+				pointer--
+			}
+		case stateScheme:
+			if isASCIIAlpha(c) || ('0' <= c && c <= '9') || c == '+' || c == '-' || c == '.' {
+				buffer = append(buffer, toASCIILower(c))
+			} else if c == ':' {
+				u.scheme = string(buffer)
+				buffer = buffer[:0]
+				switch {
+				case u.scheme == "file":
+					state = stateFile
+				case u.isSpecial() && base != nil && base.scheme == u.scheme:
+					state = stateSpecialRelativeOrAuthority
+				case u.isSpecial():
+					state = stateSpecialAuthoritySlashes
+				case len(remaining(pointer)) > 0 && remaining(pointer)[0] == '/':
+					state = statePathOrAuthority
+					pointer++
+				default:
+					opaque := ""
+					u.opaquePath = &opaque
+					state = stateOpaquePath
+				}
+			} else {
+				buffer = buffer[:0]
+				state = stateNoScheme
+				pointer = -1
+			}
+		case stateNoScheme:
+			switch {
+			case base == nil || (base.opaquePath != nil && c != '#'):
+				return nil, whatwgFailure("missing-scheme-non-relative-URL")
+			case base.opaquePath != nil && c == '#':
+				u.scheme = base.scheme
+				u.opaquePath = cloneStringPtr(base.opaquePath)
+				u.query = cloneStringPtr(base.query)
+				u.fragment = new(string)
+				state = stateFragment
+			case base.scheme != "file":
+				state = stateRelative
+				pointer--
+			default:
+				state = stateFile
+				pointer--
+			}
+		case stateSpecialRelativeOrAuthority:
+			if c == '/' && len(remaining(pointer)) > 0 && remaining(pointer)[0] == '/' {
+				state = stateSpecialAuthorityIgnoreSlashes
+				pointer++
+			} else {
+				state = stateRelative
+				pointer--
+			}
+		case statePathOrAuthority:
+			if c == '/' {
+				state = stateAuthority
+			} else {
+				state = statePath
+				pointer--
+			}
+		case stateRelative:
+			u.scheme = base.scheme
+			if c == '/' || (u.isSpecial() && c == '\\') {
+				state = stateRelativeSlash
+				continue
+			}
+			u.username, u.password = base.username, base.password
+			u.host, u.port = cloneStringPtr(base.host), base.port
+			u.path = append([]string{}, base.path...)
+			u.query = cloneStringPtr(base.query)
+			switch c {
+			case '?':
+				u.query = new(string)
+				state = stateQuery
+			case '#':
+				u.fragment = new(string)
+				state = stateFragment
+			case eof:
+			default:
+				u.query = nil
+				u.shortenPath()
+				state = statePath
+				pointer--
+			}
+		case stateRelativeSlash:
+			if u.isSpecial() && (c == '/' || c == '\\') {
+				state = stateSpecialAuthorityIgnoreSlashes
+			} else if c == '/' {
+				state = stateAuthority
+			} else {
+				u.username, u.password = base.username, base.password
+				u.host, u.port = cloneStringPtr(base.host), base.port
+				state = statePath
+				pointer--
+			}
+		case stateSpecialAuthoritySlashes:
+			if c == '/' && len(remaining(pointer)) > 0 && remaining(pointer)[0] == '/' {
+				pointer++
+			} else {
+				pointer--
+			}
+			state = stateSpecialAuthorityIgnoreSlashes
+		case stateSpecialAuthorityIgnoreSlashes:
+			if c != '/' && c != '\\' {
+				state = stateAuthority
+				pointer--
+			}
+		case stateAuthority:
+			if c == '@' {
+				if atSignSeen {
+					buffer = append([]rune("%40"), buffer...)
+				}
+				atSignSeen = true
+				for _, cp := range buffer {
+					if cp == ':' && !passwordTokenSeen {
+						passwordTokenSeen = true
+						continue
+					}
+					if passwordTokenSeen {
+						u.password += utf8PercentEncode(cp, userinfoSet)
+					} else {
+						u.username += utf8PercentEncode(cp, userinfoSet)
+					}
+				}
+				buffer = buffer[:0]
+			} else if c == eof || c == '/' || c == '?' || c == '#' || (u.isSpecial() && c == '\\') {
+				if atSignSeen && len(buffer) < 1 {
+					return nil, whatwgFailure("host-missing")
+				}
+				pointer -= len(buffer) + 1
+				buffer = buffer[:0]
+				state = stateHost
+			} else {
+				buffer = append(buffer, c)
+			}
+		case stateHost:
+			if c == ':' && !insideBrackets {
+				if len(buffer) < 1 {
+					return nil, whatwgFailure("host-missing")
+				}
+				host, err := whatwgParseHost(string(buffer), !u.isSpecial())
+				if err != nil {
+					return nil, err
+				}
+				u.host = &host
+				buffer = buffer[:0]
+				state = statePort
+			} else if c == eof || c == '/' || c == '?' || c == '#' || (u.isSpecial() && c == '\\') {
+				pointer--
+				if u.isSpecial() && len(buffer) < 1 {
+					return nil, whatwgFailure("host-missing")
+				}
+				host, err := whatwgParseHost(string(buffer), !u.isSpecial())
+				if err != nil {
+					return nil, err
+				}
+				u.host = &host
+				buffer = buffer[:0]
+				state = statePathStart
+			} else {
+				if c == '[' {
+					insideBrackets = true
+				} else if c == ']' {
+					insideBrackets = false
+				}
+				buffer = append(buffer, c)
+			}
+		case statePort:
+			if '0' <= c && c <= '9' {
+				buffer = append(buffer, c)
+			} else if c == eof || c == '/' || c == '?' || c == '#' || (u.isSpecial() && c == '\\') {
+				if len(buffer) > 0 {
+					port := 0
+					for _, d := range buffer {
+						port = port*10 + int(d-'0')
+						if port > 65535 {
+							return nil, whatwgFailure("port-out-of-range")
+						}
+					}
+					if defaultPort, ok := specialSchemes[u.scheme]; ok && defaultPort == strconv.Itoa(port) {
+						port = -1
+					}
+					u.port = port
+					buffer = buffer[:0]
+				}
+				state = statePathStart
+				pointer--
+			} else {
+				return nil, whatwgFailure("port-invalid")
+			}
+		case stateFile:
+			u.scheme = "file"
+			u.host = new(string)
+			if c == '/' || c == '\\' {
+				state = stateFileSlash
+			} else if base != nil && base.scheme == "file" {
+				u.host = cloneStringPtr(base.host)
+				u.path = append([]string{}, base.path...)
+				u.query = cloneStringPtr(base.query)
+				switch c {
+				case '?':
+					u.query = new(string)
+					state = stateQuery
+				case '#':
+					u.fragment = new(string)
+					state = stateFragment
+				case eof:
+				default:
+					u.query = nil
+					if !startsWithWindowsDriveLetter(input[pointer:]) {
+						u.shortenPath()
+					} else {
+						u.path = nil
+					}
+					state = statePath
+					pointer--
+				}
+			} else {
+				state = statePath
+				pointer--
+			}
+		case stateFileSlash:
+			if c == '/' || c == '\\' {
+				state = stateFileHost
+			} else {
+				if base != nil && base.scheme == "file" {
+					u.host = cloneStringPtr(base.host)
+					if !startsWithWindowsDriveLetter(input[pointer:]) &&
+						len(base.path) > 0 && isNormalizedWindowsDriveLetter(base.path[0]) {
+						u.path = append(u.path, base.path[0])
+					}
+				}
+				state = statePath
+				pointer--
+			}
+		case stateFileHost:
+			if c == eof || c == '/' || c == '\\' || c == '?' || c == '#' {
+				pointer--
+				if isWindowsDriveLetter(string(buffer)) {
+					// buffer is not reset here and instead used in the path state
+					state = statePath
+				} else if len(buffer) < 1 {
+					u.host = new(string)
+					state = statePathStart
+				} else {
+					host, err := whatwgParseHost(string(buffer), false)
+					if err != nil {
+						return nil, err
+					}
+					if host == "localhost" {
+						host = ""
+					}
+					u.host = &host
+					buffer = buffer[:0]
+					state = statePathStart
+				}
+			} else {
+				buffer = append(buffer, c)
+			}
+		case statePathStart:
+			if u.isSpecial() {
+				state = statePath
+				if c != '/' && c != '\\' {
+					pointer--
+				}
+			} else if c == '?' {
+				u.query = new(string)
+				state = stateQuery
+			} else if c == '#' {
+				u.fragment = new(string)
+				state = stateFragment
+			} else if c != eof {
+				state = statePath
+				if c != '/' {
+					pointer--
+				}
+			}
+		case statePath:
+			slash := c == '/' || (u.isSpecial() && c == '\\')
+			if c == eof || slash || c == '?' || c == '#' {
+				segment := string(buffer)
+				if isDoubleDotSegment(segment) {
+					u.shortenPath()
+					if !slash {
+						u.path = append(u.path, "")
+					}
+				} else if isSingleDotSegment(segment) {
+					if !slash {
+						u.path = append(u.path, "")
+					}
+				} else {
+					if u.scheme == "file" && len(u.path) < 1 && isWindowsDriveLetter(segment) {
+						segment = segment[:1] + ":"
+					}
+					u.path = append(u.path, segment)
+				}
+				buffer = buffer[:0]
+				if c == '?' {
+					u.query = new(string)
+					state = stateQuery
+				} else if c == '#' {
+					u.fragment = new(string)
+					state = stateFragment
+				}
+			} else {
+				buffer = append(buffer, []rune(utf8PercentEncode(c, pathSet))...)
+			}
+		case stateOpaquePath:
+			switch c {
+			case '?':
+				u.query = new(string)
+				state = stateQuery
+			case '#':
+				u.fragment = new(string)
+				state = stateFragment
+			case eof:
+			default:
+				*u.opaquePath += utf8PercentEncode(c, c0ControlSet)
+			}
+		case stateQuery:
+			if c == '#' || c == eof {
+				set := querySet
+				if u.isSpecial() {
+					set = specialQuerySet
+				}
+				*u.query += utf8PercentEncodeString(string(buffer), set)
+				buffer = buffer[:0]
+				if c == '#' {
+					u.fragment = new(string)
+					state = stateFragment
+				}
+			} else {
+				buffer = append(buffer, c)
+			}
+		case stateFragment:
+			if c != eof {
+				*u.fragment += utf8PercentEncode(c, fragmentSet)
+			}
+		}
+	}
+	return u, nil
+}
+
+// isSpecial url scheme is special scheme, file included
+func (u *WHATWGURL) isSpecial() bool {
+	_, ok := specialSchemes[u.scheme]
+	return ok || u.scheme == "file"
+}
+
+// shortenPath remove last path segment, normalized windows drive letter of file url kept
+func (u *WHATWGURL) shortenPath() {
+	if u.scheme == "file" && len(u.path) == 1 && isNormalizedWindowsDriveLetter(u.path[0]) {
+		return
+	}
+	if len(u.path) > 0 {
+		u.path = u.path[:len(u.path)-1]
+	}
+}
+
+// String serialized url, same as Href
+func (u *WHATWGURL) String() string {
+	var sb strings.Builder
+	sb.WriteString(u.scheme + ":")
+	if u.host != nil {
+		sb.WriteString("//")
+		if len(u.username) > 0 || len(u.password) > 0 {
+			sb.WriteString(u.username)
+			if len(u.password) > 0 {
+				sb.WriteString(":" + u.password)
+			}
+			sb.WriteString("@")
+		}
+		sb.WriteString(u.Host())
+	} else if u.opaquePath == nil && len(u.path) > 1 && len(u.path[0]) < 1 {
+		sb.WriteString("/.")
+	}
+	sb.WriteString(u.Pathname())
+	if u.query != nil {
+		sb.WriteString("?" + *u.query)
+	}
+	if u.fragment != nil {
+		sb.WriteString("#" + *u.fragment)
+	}
+	return sb.String()
+}
+
+// Href serialized url
+func (u *WHATWGURL) Href() string {
+	return u.String()
+}
+
+// Protocol scheme followed by ':'
+func (u *WHATWGURL) Protocol() string {
+	return u.scheme + ":"
+}
+
+// Username percent encoded username
+func (u *WHATWGURL) Username() string {
+	return u.username
+}
+
+// Password percent encoded password
+func (u *WHATWGURL) Password() string {
+	return u.password
+}
+
+// Host serialized host with port if any
+func (u *WHATWGURL) Host() string {
+	if u.host == nil {
+		return ""
+	}
+	if u.port < 0 {
+		return *u.host
+	}
+	return *u.host + ":" + strconv.Itoa(u.port)
+}
+
+// Hostname serialized host without port
+func (u *WHATWGURL) Hostname() string {
+	if u.host == nil {
+		return ""
+	}
+	return *u.host
+}
+
+// Port port number, empty when port is default port of special scheme or not set
+func (u *WHATWGURL) Port() string {
+	if u.port < 0 {
+		return ""
+	}
+	return strconv.Itoa(u.port)
+}
+
+// Pathname serialized path
+func (u *WHATWGURL) Pathname() string {
+	if u.opaquePath != nil {
+		return *u.opaquePath
+	}
+	var sb strings.Builder
+	for _, segment := range u.path {
+		sb.WriteString("/" + segment)
+	}
+	return sb.String()
+}
+
+// Search query prefixed with '?', empty when query is empty or not set
+func (u *WHATWGURL) Search() string {
+	if u.query == nil || len(*u.query) < 1 {
+		return ""
+	}
+	return "?" + *u.query
+}
+
+// Hash fragment prefixed with '#', empty when fragment is empty or not set
+func (u *WHATWGURL) Hash() string {
+	if u.fragment == nil || len(*u.fragment) < 1 {
+		return ""
+	}
+	return "#" + *u.fragment
+}
+
+// Origin serialized origin, "null" for opaque origin
+func (u *WHATWGURL) Origin() string {
+	switch u.scheme {
+	case "blob":
+		pathURL, err := ParseWHATWG(u.Pathname())
+		if err == nil && (pathURL.scheme == "http" || pathURL.scheme == "https") {
+			return pathURL.Origin()
+		}
+	case "ftp", "http", "https", "ws", "wss":
+		return u.scheme + "://" + u.Host()
+	}
+	return "null"
+}
+
+// netURL convert into net/url URL, keep the escaped form of path and fragment
+func (u *WHATWGURL) netURL() *url.URL {
+	uri := &url.URL{Scheme: u.scheme}
+	if len(u.username) > 0 || len(u.password) > 0 {
+		username := string(percentDecodeBytes(u.username))
+		if len(u.password) > 0 {
+			uri.User = url.UserPassword(username, string(percentDecodeBytes(u.password)))
+		} else {
+			uri.User = url.User(username)
+		}
+	}
+	if u.host != nil {
+		uri.Host = string(percentDecodeBytes(u.Host()))
+	} else {
+		uri.OmitHost = true
+	}
+	if u.opaquePath != nil {
+		uri.Opaque = *u.opaquePath
+	} else {
+		uri.RawPath = u.Pathname()
+		uri.Path = string(percentDecodeBytes(uri.RawPath))
+		if u.host == nil && strings.HasPrefix(uri.RawPath, "//") {
+			// keep path from being serialized as authority
+			uri.RawPath = "/." + uri.RawPath
+			uri.Path = "/." + uri.Path
+		}
+	}
+	if u.query != nil {
+		uri.RawQuery = *u.query
+		uri.ForceQuery = len(*u.query) < 1
+	}
+	if u.fragment != nil {
+		uri.RawFragment = *u.fragment
+		uri.Fragment = string(percentDecodeBytes(*u.fragment))
+	}
+	return uri
+}
+
+// cloneStringPtr copy of string pointer value
+func cloneStringPtr(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := *s
+	return &v
+}
+
+// isASCIIAlpha code point is ASCII alpha
+func isASCIIAlpha(c rune) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// toASCIILower ASCII lowercase of code point
+func toASCIILower(c rune) rune {
+	if 'A' <= c && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
+
+// isWindowsDriveLetter ASCII alpha followed by ':' or '|'
+func isWindowsDriveLetter(s string) bool {
+	return len(s) == 2 && isASCIIAlpha(rune(s[0])) && (s[1] == ':' || s[1] == '|')
+}
+
+// isNormalizedWindowsDriveLetter ASCII alpha followed by ':'
+func isNormalizedWindowsDriveLetter(s string) bool {
+	return isWindowsDriveLetter(s) && s[1] == ':'
+}
+
+// startsWithWindowsDriveLetter code points start with windows drive letter followed by end, '/', '\', '?' or '#'
+func startsWithWindowsDriveLetter(input []rune) bool {
+	if len(input) < 2 || !isWindowsDriveLetter(string(input[:2])) {
+		return false
+	}
+	return len(input) == 2 || strings.ContainsRune("/\\?#", input[2])
+}
+
+// isSingleDotSegment "." or "%2e"
+func isSingleDotSegment(s string) bool {
+	return s == "." || strings.EqualFold(s, "%2e")
+}
+
+// isDoubleDotSegment ".." with any dot percent encoded
+func isDoubleDotSegment(s string) bool {
+	switch strings.ToLower(s) {
+	case "..", ".%2e", "%2e.", "%2e%2e":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,431 @@
+package uruki
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// whatwgIDNA UTS #46 processing used by WHATWG domain to ASCII
+var whatwgIDNA = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.CheckJoiners(true),
+	idna.CheckHyphens(false),
+	idna.StrictDomainName(false),
+	idna.Transitional(false),
+	idna.VerifyDNSLength(false),
+)
+
+// percent-encode sets of WHATWG URL Standard, true mean code point is encoded
+var (
+	c0ControlSet = func(c rune) bool {
+		return c < 0x20 || c > 0x7e
+	}
+	fragmentSet = func(c rune) bool {
+		return c0ControlSet(c) || c == ' ' || c == '"' || c == '<' || c == '>' || c == '`'
+	}
+	querySet = func(c rune) bool {
+		return c0ControlSet(c) || c == ' ' || c == '"' || c == '#' || c == '<' || c == '>'
+	}
+	specialQuerySet = func(c rune) bool {
+		return querySet(c) || c == '\''
+	}
+	pathSet = func(c rune) bool {
+		return querySet(c) || c == '?' || c == '`' || c == '{' || c == '}'
+	}
+	userinfoSet = func(c rune) bool {
+		return pathSet(c) || c == '/' || c == ':' || c == ';' || c == '=' || c == '@' || ('[' <= c && c <= '^') || c == '|'
+	}
+)
+
+// utf8PercentEncode percent encode utf-8 bytes of code point when it is in set
+func utf8PercentEncode(c rune, set func(c rune) bool) string {
+	if !set(c) {
+		return string(c)
+	}
+	const hexUpper = "0123456789ABCDEF"
+	var sb strings.Builder
+	buf := make([]byte, utf8.UTFMax)
+	n := utf8.EncodeRune(buf, c)
+	for _, b := range buf[:n] {
+		sb.WriteByte('%')
+		sb.WriteByte(hexUpper[b>>4])
+		sb.WriteByte(hexUpper[b&15])
+	}
+	return sb.String()
+}
+
+// utf8PercentEncodeString percent encode each code point of s in set
+func utf8PercentEncodeString(s string, set func(c rune) bool) string {
+	var sb strings.Builder
+	for _, c := range s {
+		sb.WriteString(utf8PercentEncode(c, set))
+	}
+	return sb.String()
+}
+
+// percentDecodeBytes decode valid percent escape, invalid escape kept as is
+func percentDecodeBytes(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]) {
+			v, _ := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			out = append(out, byte(v))
+			i += 2
+			continue
+		}
+		out = append(out, s[i])
+	}
+	return out
+}
+
+// utf8DecodeWithoutBOM decode bytes as utf-8, invalid byte replaced with U+FFFD
+func utf8DecodeWithoutBOM(b []byte) string {
+	var sb strings.Builder
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		sb.WriteRune(r)
+		b = b[size:]
+	}
+	return sb.String()
+}
+
+// isForbiddenHostCodePoint forbidden host code point of WHATWG URL Standard
+func isForbiddenHostCodePoint(c rune) bool {
+	return strings.ContainsRune("\x00\t\n\r #/:<>?@[\\]^|", c)
+}
+
+// isForbiddenDomainCodePoint forbidden domain code point of WHATWG URL Standard
+func isForbiddenDomainCodePoint(c rune) bool {
+	return isForbiddenHostCodePoint(c) || c <= 0x1f || c == '%' || c == 0x7f
+}
+
+// whatwgParseHost host parser, return serialized host
+func whatwgParseHost(input string, isOpaque bool) (string, error) {
+	if strings.HasPrefix(input, "[") {
+		if !strings.HasSuffix(input, "]") {
+			return "", whatwgFailure("IPv6-unclosed")
+		}
+		address, err := whatwgParseIPv6([]rune(input[1 : len(input)-1]))
+		if err != nil {
+			return "", err
+		}
+		return "[" + serializeIPv6(address) + "]", nil
+	}
+	if isOpaque {
+		for _, c := range input {
+			if isForbiddenHostCodePoint(c) {
+				return "", whatwgFailure("host-invalid-code-point")
+			}
+		}
+		return utf8PercentEncodeString(input, c0ControlSet), nil
+	}
+	domain := utf8DecodeWithoutBOM(percentDecodeBytes(input))
+	asciiDomain, err := whatwgDomainToASCII(domain)
+	if err != nil {
+		return "", err
+	}
+	if endsInNumber(asciiDomain) {
+		address, err := whatwgParseIPv4(asciiDomain)
+		if err != nil {
+			return "", err
+		}
+		return serializeIPv4(address), nil
+	}
+	return asciiDomain, nil
+}
+
+// whatwgDomainToASCII domain to ASCII with UTS #46 non strict processing
+func whatwgDomainToASCII(domain string) (string, error) {
+	result := ""
+	if needIDNAProcessing(domain) {
+		ascii, err := whatwgIDNA.ToASCII(domain)
+		if err != nil {
+			return "", whatwgFailure("domain-to-ASCII")
+		}
+		result = ascii
+	} else {
+		result = strings.ToLower(domain)
+	}
+	if len(result) < 1 {
+		return "", whatwgFailure("domain-to-ASCII")
+	}
+	for _, c := range result {
+		if isForbiddenDomainCodePoint(c) {
+			return "", whatwgFailure("domain-invalid-code-point")
+		}
+	}
+	return result, nil
+}
+
+// needIDNAProcessing domain contains non ASCII or label started with punycode prefix
+func needIDNAProcessing(domain string) bool {
+	for _, label := range strings.Split(domain, ".") {
+		if len(label) >= 4 && strings.EqualFold(label[:4], "xn--") {
+			return true
+		}
+	}
+	for i := 0; i < len(domain); i++ {
+		if domain[i] >= utf8.RuneSelf {
+			return true
+		}
+	}
+	return false
+}
+
+// endsInNumber ends in a number checker
+func endsInNumber(input string) bool {
+	parts := strings.Split(input, ".")
+	if parts[len(parts)-1] == "" {
+		if len(parts) == 1 {
+			return false
+		}
+		parts = parts[:len(parts)-1]
+	}
+	last := parts[len(parts)-1]
+	if len(last) > 0 && strings.Trim(last, "0123456789") == "" {
+		return true
+	}
+	_, err := parseIPv4Number(last)
+	return err == nil
+}
+
+// whatwgParseIPv4 IPv4 parser
+func whatwgParseIPv4(input string) (uint32, error) {
+	parts := strings.Split(input, ".")
+	if parts[len(parts)-1] == "" && len(parts) > 1 {
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) > 4 {
+		return 0, whatwgFailure("IPv4-too-many-parts")
+	}
+	numbers := make([]uint64, 0, len(parts))
+	for _, part := range parts {
+		n, err := parseIPv4Number(part)
+		if err != nil {
+			return 0, whatwgFailure("IPv4-non-numeric-part")
+		}
+		numbers = append(numbers, n)
+	}
+	for _, n := range numbers[:len(numbers)-1] {
+		if n > 255 {
+			return 0, whatwgFailure("IPv4-out-of-range-part")
+		}
+	}
+	last := numbers[len(numbers)-1]
+	if float64(last) >= math.Pow(256, float64(5-len(numbers))) {
+		return 0, whatwgFailure("IPv4-out-of-range-part")
+	}
+	ipv4 := last
+	for i, n := range numbers[:len(numbers)-1] {
+		ipv4 += n << (8 * (3 - i))
+	}
+	return uint32(ipv4), nil
+}
+
+// parseIPv4Number IPv4 number parser, support hexadecimal and octal, value saturated on overflow
+func parseIPv4Number(input string) (uint64, error) {
+	if len(input) < 1 {
+		return 0, whatwgFailure("IPv4-empty-part")
+	}
+	radix := uint64(10)
+	if len(input) >= 2 && (input[:2] == "0x" || input[:2] == "0X") {
+		radix = 16
+		input = input[2:]
+	} else if len(input) >= 2 && input[0] == '0' {
+		radix = 8
+		input = input[1:]
+	}
+	if len(input) < 1 {
+		return 0, nil
+	}
+	value := uint64(0)
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		var digit uint64
+		switch {
+		case '0' <= c && c <= '9':
+			digit = uint64(c - '0')
+		case 'a' <= c && c <= 'f':
+			digit = uint64(c-'a') + 10
+		case 'A' <= c && c <= 'F':
+			digit = uint64(c-'A') + 10
+		default:
+			return 0, whatwgFailure("IPv4-non-numeric-part")
+		}
+		if digit >= radix {
+			return 0, whatwgFailure("IPv4-non-numeric-part")
+		}
+		if value > math.MaxUint64/16 {
+			// saturate, any value over 2^32 already out of range
+			continue
+		}
+		value = value*radix + digit
+	}
+	return value, nil
+}
+
+// serializeIPv4 dotted decimal of IPv4 address
+func serializeIPv4(address uint32) string {
+	parts := make([]string, 4)
+	for i := 3; i >= 0; i-- {
+		parts[i] = strconv.Itoa(int(address % 256))
+		address /= 256
+	}
+	return strings.Join(parts, ".")
+}
+
+// whatwgParseIPv6 IPv6 parser
+func whatwgParseIPv6(input []rune) ([8]uint16, error) {
+	address := [8]uint16{}
+	pieceIndex := 0
+	compress := -1
+	pointer := 0
+	at := func(i int) rune {
+		if i < len(input) {
+			return input[i]
+		}
+		return -1
+	}
+	if at(pointer) == ':' {
+		if at(pointer+1) != ':' {
+			return address, whatwgFailure("IPv6-invalid-compression")
+		}
+		pointer += 2
+		pieceIndex++
+		compress = pieceIndex
+	}
+	for at(pointer) != -1 {
+		if pieceIndex == 8 {
+			return address, whatwgFailure("IPv6-too-many-pieces")
+		}
+		if at(pointer) == ':' {
+			if compress != -1 {
+				return address, whatwgFailure("IPv6-multiple-compression")
+			}
+			pointer++
+			pieceIndex++
+			compress = pieceIndex
+			continue
+		}
+		value, length := 0, 0
+		for length < 4 && at(pointer) != -1 && at(pointer) < utf8.RuneSelf && isHex(byte(at(pointer))) {
+			v, _ := strconv.ParseUint(string(at(pointer)), 16, 8)
+			value = value*0x10 + int(v)
+			pointer++
+			length++
+		}
+		if at(pointer) == '.' {
+			if length == 0 {
+				return address, whatwgFailure("IPv4-in-IPv6-invalid-code-point")
+			}
+			pointer -= length
+			if pieceIndex > 6 {
+				return address, whatwgFailure("IPv4-in-IPv6-too-many-pieces")
+			}
+			numbersSeen := 0
+			for at(pointer) != -1 {
+				ipv4Piece := -1
+				if numbersSeen > 0 {
+					if at(pointer) == '.' && numbersSeen < 4 {
+						pointer++
+					} else {
+						return address, whatwgFailure("IPv4-in-IPv6-invalid-code-point")
+					}
+				}
+				if c := at(pointer); c < '0' || c > '9' {
+					return address, whatwgFailure("IPv4-in-IPv6-invalid-code-point")
+				}
+				for c := at(pointer); '0' <= c && c <= '9'; c = at(pointer) {
+					number := int(c - '0')
+					if ipv4Piece == -1 {
+						ipv4Piece = number
+					} else if ipv4Piece == 0 {
+						return address, whatwgFailure("IPv4-in-IPv6-invalid-code-point")
+					} else {
+						ipv4Piece = ipv4Piece*10 + number
+					}
+					if ipv4Piece > 255 {
+						return address, whatwgFailure("IPv4-in-IPv6-out-of-range-part")
+					}
+					pointer++
+				}
+				address[pieceIndex] = address[pieceIndex]*0x100 + uint16(ipv4Piece)
+				numbersSeen++
+				if numbersSeen == 2 || numbersSeen == 4 {
+					pieceIndex++
+				}
+			}
+			if numbersSeen != 4 {
+				return address, whatwgFailure("IPv4-in-IPv6-too-few-parts")
+			}
+			break
+		} else if at(pointer) == ':' {
+			pointer++
+			if at(pointer) == -1 {
+				return address, whatwgFailure("IPv6-invalid-code-point")
+			}
+		} else if at(pointer) != -1 {
+			return address, whatwgFailure("IPv6-invalid-code-point")
+		}
+		address[pieceIndex] = uint16(value)
+		pieceIndex++
+	}
+	if compress != -1 {
+		swaps := pieceIndex - compress
+		pieceIndex = 7
+		for pieceIndex != 0 && swaps > 0 {
+			address[pieceIndex], address[compress+swaps-1] = address[compress+swaps-1], address[pieceIndex]
+			pieceIndex--
+			swaps--
+		}
+	} else if pieceIndex != 8 {
+		return address, whatwgFailure("IPv6-too-few-pieces")
+	}
+	return address, nil
+}
+
+// serializeIPv6 IPv6 serializer with first longest zero sequence compressed
+func serializeIPv6(address [8]uint16) string {
+	compress, longest := -1, 1
+	for i := 0; i < 8; {
+		if address[i] != 0 {
+			i++
+			continue
+		}
+		j := i
+		for j < 8 && address[j] == 0 {
+			j++
+		}
+		if j-i > longest {
+			compress, longest = i, j-i
+		}
+		i = j
+	}
+	var sb strings.Builder
+	ignore0 := false
+	for i := 0; i < 8; i++ {
+		if ignore0 && address[i] == 0 {
+			continue
+		}
+		ignore0 = false
+		if compress == i {
+			if i == 0 {
+				sb.WriteString("::")
+			} else {
+				sb.WriteString(":")
+			}
+			ignore0 = true
+			continue
+		}
+		sb.WriteString(strconv.FormatUint(uint64(address[i]), 16))
+		if i != 7 {
+			sb.WriteString(":")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,180 @@
+package uruki
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+)
+
+// urlTestCase test case of web-platform-tests urltestdata.json
+type urlTestCase struct {
+	Input    string  `json:"input"`
+	Base     *string `json:"base"`
+	Failure  bool    `json:"failure"`
+	Href     string  `json:"href"`
+	Origin   *string `json:"origin"`
+	Protocol string  `json:"protocol"`
+	Username string  `json:"username"`
+	Password string  `json:"password"`
+	Host     string  `json:"host"`
+	Hostname string  `json:"hostname"`
+	Port     string  `json:"port"`
+	Pathname string  `json:"pathname"`
+	Search   string  `json:"search"`
+	Hash     string  `json:"hash"`
+}
+
+func Test_ParseWHATWGTestData(t *testing.T) {
+	data, err := os.ReadFile("testdata/urltestdata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := make([]json.RawMessage, 0)
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatal(err)
+	}
+	for i, entry := range entries {
+		tt := urlTestCase{}
+		if err := json.Unmarshal(entry, &tt); err != nil {
+			// string entry is comment
+			continue
+		}
+		var base *WHATWGURL
+		if tt.Base != nil {
+			base, err = ParseWHATWG(*tt.Base)
+			if err != nil {
+				t.Errorf("fail test ParseWHATWG() #%d base %q got error %v", i, *tt.Base, err)
+				continue
+			}
+		}
+		got, err := whatwgParse(tt.Input, base)
+		if tt.Failure {
+			if err == nil {
+				t.Errorf("fail test ParseWHATWG() #%d input %q got %v want failure", i, tt.Input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("fail test ParseWHATWG() #%d input %q got error %v want %v", i, tt.Input, err, tt.Href)
+			continue
+		}
+		checks := []struct {
+			name string
+			got  string
+			want string
+		}{
+			{"href", got.Href(), tt.Href},
+			{"protocol", got.Protocol(), tt.Protocol},
+			{"username", got.Username(), tt.Username},
+			{"password", got.Password(), tt.Password},
+			{"host", got.Host(), tt.Host},
+			{"hostname", got.Hostname(), tt.Hostname},
+			{"port", got.Port(), tt.Port},
+			{"pathname", got.Pathname(), tt.Pathname},
+			{"search", got.Search(), tt.Search},
+			{"hash", got.Hash(), tt.Hash},
+		}
+		if tt.Origin != nil {
+			checks = append(checks, struct {
+				name string
+				got  string
+				want string
+			}{"origin", got.Origin(), *tt.Origin})
+		}
+		for _, c := range checks {
+			if c.got != c.want {
+				t.Errorf("fail test ParseWHATWG() #%d input %q %s got %q want %q", i, tt.Input, c.name, c.got, c.want)
+			}
+		}
+	}
+}
+
+func Test_ParseModeWHATWG(t *testing.T) {
+	type args struct {
+		name    string
+		url     string
+		mode    ParseMode
+		want    string
+		wantErr error
+	}
+
+	testCases := []args{
+		{
+			name: "net url keep backslash and default port",
+			url:  "https://www.tokopedia.com:443/disco\\very",
+			mode: NetURL,
+			want: "https://www.tokopedia.com:443/disco%5Cvery",
+		},
+		{
+			name: "whatwg backslash, tab and default port",
+			url:  "  HTTPS://www.Tokopedia.com:443\\disco\tvery\\..\\promo?q=a b#top ",
+			mode: WHATWG,
+			want: "https://www.tokopedia.com/promo?q=a%20b#top",
+		},
+		{
+			name: "whatwg ipv4 number forms",
+			url:  "http://0x7f.1/login",
+			mode: WHATWG,
+			want: "http://127.0.0.1/login",
+		},
+		{
+			name: "whatwg opaque path",
+			url:  "mailto:cs@tokopedia.com?subject=hi",
+			mode: WHATWG,
+			want: "mailto:cs@tokopedia.com?subject=hi",
+		},
+		{
+			name: "whatwg idna host",
+			url:  "https://bücher.example/",
+			mode: WHATWG,
+			want: "https://xn--bcher-kva.example/",
+		},
+		{
+			name:    "whatwg failure",
+			url:     "https://exa mple.com",
+			mode:    WHATWG,
+			wantErr: ErrorWHATWGFailure,
+		},
+		{
+			name: "whatwg empty url",
+			url:  "",
+			mode: WHATWG,
+			want: "",
+		},
+		{
+			name:    "rfc3986 strict reject raw space",
+			url:     "https://www.tokopedia.com/search?q=macbook air",
+			mode:    RFC3986Strict,
+			wantErr: ErrorRFC3986Invalid,
+		},
+		{
+			name:    "rfc3986 strict reject invalid escape",
+			url:     "https://www.tokopedia.com/search?q=100%",
+			mode:    RFC3986Strict,
+			wantErr: ErrorRFC3986Invalid,
+		},
+		{
+			name: "rfc3986 strict valid",
+			url:  "https://www.tokopedia.com/search?q=macbook%20air",
+			mode: RFC3986Strict,
+			want: "https://www.tokopedia.com/search?q=macbook%20air",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ub, err := NewBuilder(Option{URL: tt.url, ParseMode: tt.mode})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("fail test ParseMode got error %v want %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got := ub.GetURLResult(); got != tt.want {
+				t.Errorf("fail test ParseMode got %v want %v", got, tt.want)
+			}
+		})
+	}
+}